@@ -0,0 +1,356 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BigIntJSONString makes MarshalJSON write BigInts as JSON strings instead of
+// numbers, for clients that would read numbers into a float64, such as
+// JavaScript. UnmarshalJSON accepts both either way.
+var BigIntJSONString = false
+
+// BigInt is a nullable arbitrary precision integer, for order numbers,
+// blockchain amounts and NUMERIC(38,0) columns beyond the int64 range. It
+// follows the null semantics of Int. A nil Int in a valid BigInt is zero.
+//
+// The constructors and decoders store a big.Int of their own, and
+// ValueOrZero returns a copy, so values don't share memory with the caller.
+type BigInt struct {
+	Int   *big.Int
+	Valid bool
+}
+
+// NewBigInt creates a BigInt holding a copy of i.
+func NewBigInt(i *big.Int, valid bool) BigInt {
+	if !valid {
+		return BigInt{}
+	}
+	n := new(big.Int)
+	if i != nil {
+		n.Set(i)
+	}
+	return BigInt{Int: n, Valid: true}
+}
+
+// BigIntFrom creates a BigInt from a copy of i under DefaultPolicy. A nil i
+// is null.
+func BigIntFrom(i *big.Int) BigInt {
+	return NewBigInt(i, i != nil).WithPolicy(DefaultPolicy)
+}
+
+// BigIntFromInt64 creates a BigInt from i under DefaultPolicy.
+func BigIntFromInt64(i int64) BigInt {
+	return BigIntFrom(big.NewInt(i))
+}
+
+// ValueOrZero returns a copy of the value, or a new zero big.Int if it is
+// null.
+func (b BigInt) ValueOrZero() *big.Int {
+	n := new(big.Int)
+	if b.Valid && b.Int != nil {
+		n.Set(b.Int)
+	}
+	return n
+}
+
+// IsZero reports whether the value is null.
+func (b BigInt) IsZero() bool {
+	return !b.Valid
+}
+
+// String returns the value in base 10, or "" if it is null.
+func (b BigInt) String() string {
+	if !b.Valid {
+		return ""
+	}
+	return b.int().String()
+}
+
+// int returns the value, or zero for a nil Int. It must not be modified.
+func (b BigInt) int() *big.Int {
+	if b.Int == nil {
+		return new(big.Int)
+	}
+	return b.Int
+}
+
+// WithPolicy returns the value nulled according to p.
+func (b BigInt) WithPolicy(p Policy) BigInt {
+	if !b.Valid || (p.ZeroAsNull && b.int().Sign() == 0) {
+		return BigInt{}
+	}
+	return b
+}
+
+func (b *BigInt) applyPolicy(p Policy) {
+	*b = b.WithPolicy(p)
+}
+
+// parseBigInt parses base 10 text, in any form ParseDecimal accepts as long
+// as it has no fractional part, such as "1e20" or "12.00". Blank text is
+// null.
+func parseBigInt(s string) (BigInt, error) {
+	d, err := parseDecimal(strings.TrimSpace(s))
+	if err != nil {
+		return BigInt{}, err
+	}
+	return integralDecimal(d)
+}
+
+// integralDecimal returns the integer d is worth, failing for fractions.
+// DECIMAL columns with a scale return integers as text such as "12.00".
+func integralDecimal(d Decimal) (BigInt, error) {
+	if !d.Valid {
+		return BigInt{}, nil
+	}
+	q := d.Quantize(0, RoundDown)
+	if !q.Equal(d) {
+		return BigInt{}, strconv.ErrSyntax
+	}
+	return BigInt{Int: q.Unscaled(), Valid: true}, nil
+}
+
+func (b BigInt) EncodeValues(key string, v *url.Values) error {
+	return b.encodeValues(key, v, DefaultPolicy)
+}
+
+func (b BigInt) encodeValues(key string, v *url.Values, p Policy) error {
+	b = b.WithPolicy(p)
+	if !b.Valid {
+		return nil
+	}
+	v.Set(key, b.String())
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The value is written as a number, or as a string if BigIntJSONString is
+// set.
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return b.marshalJSON(DefaultPolicy)
+}
+
+func (b BigInt) marshalJSON(p Policy) ([]byte, error) {
+	b = b.WithPolicy(p)
+	if !b.Valid {
+		return []byte("null"), nil
+	}
+	if BigIntJSONString {
+		return json.Marshal(b.String())
+	}
+	return []byte(b.String()), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts null, and numbers and strings holding an integer, including the
+// exponent form JavaScript writes for large numbers such as 1e+21; "" is
+// null.
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	return b.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (b *BigInt) unmarshalJSON(data []byte, p Policy) error {
+	v, err := decodeLenientJSON(data, "BigInt")
+	if err != nil {
+		*b = BigInt{}
+		return err
+	}
+	switch s := v.(type) {
+	case nil:
+		*b = BigInt{}
+	case string:
+		return b.unmarshalText([]byte(s), p)
+	case json.Number:
+		return b.unmarshalText([]byte(s), p)
+	default:
+		*b = BigInt{}
+		return &ScanError{Src: s, Type: "BigInt", Err: ErrUnsupportedType}
+	}
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (b BigInt) MarshalText() ([]byte, error) {
+	return b.marshalText(DefaultPolicy)
+}
+
+func (b BigInt) marshalText(p Policy) ([]byte, error) {
+	return []byte(b.WithPolicy(p).String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Empty text and "null" are null.
+func (b *BigInt) UnmarshalText(text []byte) error {
+	return b.unmarshalText(text, DefaultPolicy)
+}
+
+func (b *BigInt) unmarshalText(text []byte, p Policy) error {
+	s := p.text(string(text))
+	if s == "null" {
+		*b = BigInt{}
+		return nil
+	}
+	v, err := parseBigInt(s)
+	if err != nil {
+		*b = BigInt{}
+		return &ScanError{Src: s, Type: "BigInt", Err: err}
+	}
+	*b = v.WithPolicy(p)
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// The value is written as an integer of any size.
+func (b BigInt) MarshalYAML() (any, error) {
+	return b.marshalYAML(DefaultPolicy)
+}
+
+func (b BigInt) marshalYAML(p Policy) (any, error) {
+	b = b.WithPolicy(p)
+	if !b.Valid {
+		return nil, nil
+	}
+	return yamlNumber(b.String()), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The value is parsed the same way as UnmarshalText.
+func (b *BigInt) UnmarshalYAML(value *yaml.Node) error {
+	return b.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (b *BigInt) unmarshalYAML(value *yaml.Node, p Policy) error {
+	s, valid, err := yamlText(value, "nulled.BigInt")
+	if err != nil || !valid {
+		*b = BigInt{}
+		return err
+	}
+	return b.unmarshalText([]byte(s), p)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (b BigInt) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	b = b.WithPolicy(DefaultPolicy)
+	return marshalXMLElement(e, start, b.String(), b.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (b *BigInt) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*b = BigInt{}
+		return nil
+	}
+	return b.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (b BigInt) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	b = b.WithPolicy(DefaultPolicy)
+	if !b.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: b.String()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (b *BigInt) UnmarshalXMLAttr(attr xml.Attr) error {
+	return b.UnmarshalText([]byte(attr.Value))
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts whatever Decimal.Scan does, including the text that drivers
+// return for NUMERIC and DECIMAL columns, as long as it has no fractional
+// part: "12.00" is 12, "12.5" is an error. Blank text is scanned as null.
+func (b *BigInt) Scan(value any) error {
+	return b.scan(value, DefaultPolicy)
+}
+
+func (b *BigInt) scan(value any, p Policy) error {
+	var d Decimal
+	if err := d.scan(value, Policy{}); err != nil {
+		if scanErr, ok := err.(*ScanError); ok {
+			scanErr.Type = "BigInt"
+		}
+		return err
+	}
+	v, err := integralDecimal(d)
+	if err != nil {
+		return &ScanError{Src: value, Type: "BigInt", Err: err}
+	}
+	*b = v.WithPolicy(p)
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// Values in the int64 range are written as an int64, larger ones as base 10
+// text, which NUMERIC and DECIMAL columns accept.
+func (b BigInt) Value() (driver.Value, error) {
+	return b.value(DefaultPolicy)
+}
+
+func (b BigInt) value(p Policy) (driver.Value, error) {
+	b = b.WithPolicy(p)
+	if !b.Valid {
+		return nil, nil
+	}
+	if n := b.int(); n.IsInt64() {
+		return n.Int64(), nil
+	}
+	return b.String(), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (b BigInt) MarshalBinary() ([]byte, error) {
+	data := binaryHeader(b.Valid)
+	if !b.Valid {
+		return data, nil
+	}
+	n, err := b.int().GobEncode()
+	if err != nil {
+		return nil, err
+	}
+	return append(data, n...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (b *BigInt) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*b = BigInt{}
+		return nil
+	}
+	n := new(big.Int)
+	if len(p) == 0 || n.GobDecode(p) != nil {
+		return ErrInvalidBinary
+	}
+	*b = BigInt{Int: n, Valid: true}
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (b BigInt) GobEncode() ([]byte, error) {
+	return b.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (b *BigInt) GobDecode(data []byte) error {
+	return b.UnmarshalBinary(data)
+}
@@ -0,0 +1,249 @@
+package nulled
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"math/big"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func mustBigInt(t *testing.T, s string) BigInt {
+	t.Helper()
+	n, ok := new(big.Int).SetString(s, 10)
+	require.True(t, ok)
+	return BigIntFrom(n)
+}
+
+func TestBigInt_Constructors(t *testing.T) {
+	n := big.NewInt(5)
+	b := NewBigInt(n, true)
+	n.SetInt64(6)
+	assert.Equal(t, "5", b.String(), "value is copied")
+	b.ValueOrZero().SetInt64(7)
+	assert.Equal(t, "5", b.String(), "ValueOrZero returns a copy")
+
+	assert.False(t, BigIntFrom(nil).Valid)
+	assert.False(t, NewBigInt(n, false).Valid)
+	assert.Equal(t, "0", NewBigInt(nil, true).String())
+	assert.Equal(t, "0", BigInt{Valid: true}.String())
+	assert.Equal(t, big.NewInt(0), BigInt{}.ValueOrZero())
+	assert.Equal(t, "-42", BigIntFromInt64(-42).String())
+	assert.True(t, BigInt{}.IsZero())
+	assert.False(t, BigIntFromInt64(0).IsZero())
+}
+
+func TestBigInt_JSON(t *testing.T) {
+	type transfer struct {
+		Amount BigInt `json:"amount"`
+		Fee    BigInt `json:"fee"`
+	}
+
+	var in transfer
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": 123456789012345678901234567890, "fee": "-21000"}`), &in))
+	assert.Equal(t, "123456789012345678901234567890", in.Amount.String())
+	assert.Equal(t, "-21000", in.Fee.String())
+
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":123456789012345678901234567890,"fee":-21000}`, string(data))
+
+	BigIntJSONString = true
+	t.Cleanup(func() { BigIntJSONString = false })
+	data, err = json.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":"123456789012345678901234567890","fee":"-21000"}`, string(data))
+
+	data, err = json.Marshal(transfer{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"amount":null,"fee":null}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount": null, "fee": ""}`), &in))
+	assert.False(t, in.Amount.Valid)
+	assert.False(t, in.Fee.Valid)
+
+	var b BigInt
+	require.NoError(t, b.UnmarshalJSON([]byte(`1e+21`)))
+	assert.Equal(t, "1000000000000000000000", b.String())
+	require.NoError(t, b.UnmarshalJSON([]byte(`"12.00"`)))
+	assert.Equal(t, "12", b.String())
+	assert.ErrorIs(t, b.UnmarshalJSON([]byte(`1.5`)), strconv.ErrSyntax)
+	assert.ErrorIs(t, b.UnmarshalJSON([]byte(`1e-3`)), strconv.ErrSyntax)
+	assert.ErrorIs(t, b.UnmarshalJSON([]byte(`1e999999999`)), strconv.ErrRange)
+	assert.ErrorIs(t, b.UnmarshalJSON([]byte(`"0x10"`)), strconv.ErrSyntax)
+	assert.ErrorIs(t, b.UnmarshalJSON([]byte(`true`)), ErrUnsupportedType)
+	assert.Error(t, b.UnmarshalJSON([]byte(`{}`)))
+	assert.False(t, b.Valid)
+}
+
+func TestBigInt_Text(t *testing.T) {
+	text, err := mustBigInt(t, "-99999999999999999999").MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "-99999999999999999999", string(text))
+
+	text, err = BigInt{}.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	var b BigInt
+	require.NoError(t, b.UnmarshalText([]byte(" 18446744073709551616 ")))
+	assert.Equal(t, mustBigInt(t, "18446744073709551616"), b)
+	require.NoError(t, b.UnmarshalText([]byte("null")))
+	assert.False(t, b.Valid)
+	assert.ErrorIs(t, b.UnmarshalText([]byte("1,000")), strconv.ErrSyntax)
+}
+
+func TestBigInt_YAML(t *testing.T) {
+	type order struct {
+		ID   BigInt `yaml:"id"`
+		Ref  BigInt `yaml:"ref"`
+		Next BigInt `yaml:"next"`
+	}
+	n, ok := new(big.Int).SetString("-123456789012345678901234567890", 10)
+	require.True(t, ok)
+	in := order{ID: BigIntFromInt64(42), Next: BigIntFrom(n)}
+	data, err := yaml.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, "id: 42\nref: null\nnext: -123456789012345678901234567890\n", string(data))
+
+	var out order
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+	require.NoError(t, yaml.Unmarshal([]byte("id: \"7\"\n"), &out))
+	assert.Equal(t, BigIntFromInt64(7), out.ID)
+	assert.Error(t, yaml.Unmarshal([]byte("id: 1.5"), &out))
+	assert.Error(t, yaml.Unmarshal([]byte("id: {a: 1}"), &out))
+}
+
+func TestBigInt_XML(t *testing.T) {
+	type order struct {
+		XMLName xml.Name `xml:"order"`
+		ID      BigInt   `xml:"id,attr"`
+		Ref     BigInt   `xml:"ref"`
+	}
+	data, err := xml.Marshal(order{ID: BigIntFromInt64(42)})
+	require.NoError(t, err)
+	assert.Equal(t, `<order id="42"><ref xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></ref></order>`, string(data))
+
+	var out order
+	require.NoError(t, xml.Unmarshal([]byte(`<order id="42"><ref>123456789012345678901234567890</ref></order>`), &out))
+	assert.Equal(t, BigIntFromInt64(42), out.ID)
+	assert.Equal(t, "123456789012345678901234567890", out.Ref.String())
+	assert.Error(t, xml.Unmarshal([]byte(`<order id="x"></order>`), &out))
+}
+
+func TestBigInt_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, mustBigInt(t, "340282366920938463463374607431768211455").EncodeValues("id", &v))
+	require.NoError(t, BigIntFromInt64(0).EncodeValues("zero", &v))
+	require.NoError(t, BigInt{}.EncodeValues("skip", &v))
+	assert.Equal(t, url.Values{"id": {"340282366920938463463374607431768211455"}, "zero": {"0"}}, v)
+}
+
+func TestBigInt_Scan(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   any
+		want  string
+		valid bool
+	}{
+		{name: "numeric bytes", src: []byte("99999999999999999999999999999999999999"), want: "99999999999999999999999999999999999999", valid: true},
+		{name: "decimal string", src: "-12.00", want: "-12", valid: true},
+		{name: "int64", src: int64(42), want: "42", valid: true},
+		{name: "uint64", src: uint64(18446744073709551615), want: "18446744073709551615", valid: true},
+		{name: "float64", src: 1e20, want: "100000000000000000000", valid: true},
+		{name: "nil", src: nil, valid: false},
+		{name: "blank", src: " ", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := BigIntFromInt64(1)
+			require.NoError(t, b.Scan(tt.src))
+			assert.Equal(t, tt.valid, b.Valid)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+
+	b := BigIntFromInt64(1)
+	assert.ErrorIs(t, b.Scan("12.5"), strconv.ErrSyntax)
+	assert.ErrorIs(t, b.Scan(0.5), strconv.ErrSyntax)
+	err := b.Scan(struct{}{})
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	assert.Equal(t, "1", b.String(), "unchanged on error")
+	var scanErr *ScanError
+	require.ErrorAs(t, err, &scanErr)
+	assert.Equal(t, "BigInt", scanErr.Type)
+}
+
+func TestBigInt_Value(t *testing.T) {
+	v, err := BigIntFromInt64(-7).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(-7), v)
+
+	v, err = mustBigInt(t, "9223372036854775808").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "9223372036854775808", v)
+
+	v, err = BigInt{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestBigInt_SQL(t *testing.T) {
+	db := openTestDB(t)
+
+	_, err := db.Exec("INSERT", mustBigInt(t, "-123456789012345678901234567890"), BigIntFromInt64(5), BigInt{})
+	require.NoError(t, err)
+
+	var a, b, c BigInt
+	c = BigIntFromInt64(1)
+	require.NoError(t, db.QueryRow("SELECT").Scan(&a, &b, &c))
+	assert.Equal(t, "-123456789012345678901234567890", a.String())
+	assert.Equal(t, "5", b.String())
+	assert.False(t, c.Valid)
+}
+
+func TestBigInt_Binary(t *testing.T) {
+	for _, s := range []string{"0", "-1", "123456789012345678901234567890"} {
+		b := mustBigInt(t, s)
+		data, err := b.MarshalBinary()
+		require.NoError(t, err)
+
+		var got BigInt
+		require.NoError(t, got.UnmarshalBinary(data))
+		assert.Equal(t, b, got)
+	}
+
+	data, err := BigInt{}.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0}, data)
+
+	var b BigInt
+	assert.ErrorIs(t, b.UnmarshalBinary(nil), ErrInvalidBinary)
+	assert.ErrorIs(t, b.UnmarshalBinary([]byte{1}), ErrInvalidBinary)
+
+	var buf bytes.Buffer
+	in := mustBigInt(t, "-98765432109876543210")
+	require.NoError(t, gob.NewEncoder(&buf).Encode(in))
+	var out BigInt
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	assert.Equal(t, in, out)
+}
+
+func TestBigInt_Policy(t *testing.T) {
+	zero := Policy{ZeroAsNull: true}
+	assert.False(t, BigIntFromInt64(0).WithPolicy(zero).Valid)
+	assert.True(t, BigIntFromInt64(1).WithPolicy(zero).Valid)
+
+	var b BigInt
+	require.NoError(t, UnmarshalTextWithPolicy(&b, []byte(" 0 "), zero))
+	assert.False(t, b.Valid)
+}
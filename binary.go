@@ -0,0 +1,78 @@
+package nulled
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+)
+
+// ErrInvalidBinary is returned by UnmarshalBinary and GobDecode for data that
+// wasn't produced by MarshalBinary or a previous version's GobEncode.
+var ErrInvalidBinary = errors.New("nulled: invalid binary data")
+
+// The binary form of every type is one validity byte followed, for valid
+// values only, by the value itself:
+//
+//	Int        signed varint
+//	Float      8 bytes, big-endian IEEE 754 bits
+//	Bool       1 byte, 0 or 1
+//	String     uvarint length followed by the bytes
+//	Time       time.Time.MarshalBinary output
+//	Date       signed varint year, 1 byte month, 1 byte day
+//	TimeOfDay  uvarint nanoseconds since midnight
+//	Duration   signed varint nanoseconds
+//	Int8-32    signed varint
+//	Uint-64    uvarint
+//	Float32    same as Float
+//	Decimal    uvarint scale, then big.Int.GobEncode output
+//	BigInt     big.Int.GobEncode output
+//	Bytes      the bytes
+//
+// String, Int, Float, Bool and Time call their codecs directly instead of
+// going through Value, whose codec lookup would cost more than the encoding.
+const (
+	binaryNull  byte = 0
+	binaryValid byte = 1
+)
+
+// binaryHeader returns the first byte of a binary value.
+func binaryHeader(valid bool) []byte {
+	if valid {
+		return []byte{binaryValid}
+	}
+	return []byte{binaryNull}
+}
+
+// readBinaryHeader splits data into its validity flag and payload.
+func readBinaryHeader(data []byte) ([]byte, bool, error) {
+	if len(data) == 0 {
+		return nil, false, ErrInvalidBinary
+	}
+	switch data[0] {
+	case binaryNull:
+		if len(data) != 1 {
+			return nil, false, ErrInvalidBinary
+		}
+		return nil, false, nil
+	case binaryValid:
+		return data[1:], true, nil
+	}
+	return nil, false, ErrInvalidBinary
+}
+
+// isLegacyGob reports whether data was written by the GobEncode of earlier
+// versions, which wrote the value and the Valid flag as two gob streams. A gob
+// stream starts with a message length of at least 3, so it never begins with
+// a validity byte.
+func isLegacyGob(data []byte) bool {
+	return len(data) > 0 && data[0] > binaryValid
+}
+
+// decodeLegacyGob reads the value and the Valid flag of a legacy gob blob.
+func decodeLegacyGob(data []byte, value any, valid *bool) error {
+	dec := gob.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(value); err != nil {
+		return err
+	}
+	return dec.Decode(valid)
+}
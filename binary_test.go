@@ -0,0 +1,168 @@
+package nulled
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacyGobEncode reproduces the GobEncode of earlier versions, which wrote
+// the value and the Valid flag as two gob streams.
+func legacyGobEncode(value any, valid bool) []byte {
+	var buf bytes.Buffer
+	enc := gob.NewEncoder(&buf)
+	if err := enc.Encode(value); err != nil {
+		panic(err)
+	}
+	if err := enc.Encode(valid); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+type binaryCodec interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func TestBinary_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value binaryCodec
+		want  []byte
+		empty func() binaryCodec
+	}{
+		{name: "int", value: ptr(IntFrom(-3)), want: []byte{1, 5}, empty: func() binaryCodec { return new(Int) }},
+		{name: "max int", value: ptr(IntFrom(math.MaxInt64)), empty: func() binaryCodec { return new(Int) }},
+		{name: "null int", value: ptr(NewInt(0, false)), want: []byte{0}, empty: func() binaryCodec { return new(Int) }},
+		{name: "float", value: ptr(FloatFrom(1)), want: []byte{1, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0}, empty: func() binaryCodec { return new(Float) }},
+		{name: "null float", value: ptr(NewFloat(0, false)), want: []byte{0}, empty: func() binaryCodec { return new(Float) }},
+		{name: "bool", value: ptr(BoolFrom(true)), want: []byte{1, 1}, empty: func() binaryCodec { return new(Bool) }},
+		{name: "false", value: ptr(BoolFrom(false)), want: []byte{1, 0}, empty: func() binaryCodec { return new(Bool) }},
+		{name: "null bool", value: ptr(NewBool(false, false)), want: []byte{0}, empty: func() binaryCodec { return new(Bool) }},
+		{name: "string", value: ptr(StringFrom("hi")), want: []byte{1, 2, 'h', 'i'}, empty: func() binaryCodec { return new(String) }},
+		{name: "empty string", value: ptr(NewString("", true)), want: []byte{1, 0}, empty: func() binaryCodec { return new(String) }},
+		{name: "null string", value: ptr(NewString("", false)), want: []byte{0}, empty: func() binaryCodec { return new(String) }},
+		{name: "time", value: ptr(TimeFrom(testTime)), empty: func() binaryCodec { return new(Time) }},
+		{name: "null time", value: ptr(NewTime(time.Time{}, false)), want: []byte{0}, empty: func() binaryCodec { return new(Time) }},
+		{name: "date", value: ptr(DateFrom(2023, time.October, 27)), want: []byte{1, 0xce, 0x1f, 10, 27}, empty: func() binaryCodec { return new(Date) }},
+		{name: "null date", value: ptr(Date{}), want: []byte{0}, empty: func() binaryCodec { return new(Date) }},
+		{name: "time of day", value: ptr(TimeOfDayFrom(0, 0, 1, 0)), want: []byte{1, 0x80, 0x94, 0xeb, 0xdc, 0x03}, empty: func() binaryCodec { return new(TimeOfDay) }},
+		{name: "null time of day", value: ptr(TimeOfDay{}), want: []byte{0}, empty: func() binaryCodec { return new(TimeOfDay) }},
+		{name: "duration", value: ptr(DurationFrom(-3)), want: []byte{1, 5}, empty: func() binaryCodec { return new(Duration) }},
+		{name: "null duration", value: ptr(NewDuration(0, false)), want: []byte{0}, empty: func() binaryCodec { return new(Duration) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.value.MarshalBinary()
+			require.NoError(t, err)
+			if tt.want != nil {
+				assert.Equal(t, tt.want, data)
+			}
+
+			decoded := tt.empty()
+			require.NoError(t, decoded.UnmarshalBinary(data))
+			assert.Equal(t, tt.value, decoded)
+		})
+	}
+}
+
+func TestBinary_Invalid(t *testing.T) {
+	for _, data := range [][]byte{nil, {2}, {0, 1}, {1}} {
+		assert.ErrorIs(t, new(Int).UnmarshalBinary(data), ErrInvalidBinary)
+		assert.ErrorIs(t, new(Float).UnmarshalBinary(data), ErrInvalidBinary)
+		assert.ErrorIs(t, new(Bool).UnmarshalBinary(data), ErrInvalidBinary)
+		assert.ErrorIs(t, new(Time).UnmarshalBinary(data), ErrInvalidBinary)
+		assert.ErrorIs(t, new(Date).UnmarshalBinary(data), ErrInvalidBinary)
+		assert.ErrorIs(t, new(TimeOfDay).UnmarshalBinary(data), ErrInvalidBinary)
+	}
+	assert.ErrorIs(t, new(Date).UnmarshalBinary([]byte{1, 0xce, 0x1f, 13, 1}), ErrInvalidBinary)
+	assert.ErrorIs(t, new(String).UnmarshalBinary([]byte{1, 5, 'a'}), ErrInvalidBinary)
+	assert.ErrorIs(t, new(Bool).UnmarshalBinary([]byte{1, 2}), ErrInvalidBinary)
+}
+
+func TestBinary_LegacyGobDecode(t *testing.T) {
+	var i Int
+	require.NoError(t, i.GobDecode(legacyGobEncode(int64(42), true)))
+	assert.Equal(t, IntFrom(42), i)
+	require.NoError(t, i.GobDecode(legacyGobEncode(int64(0), false)))
+	assert.Equal(t, NewInt(0, false), i)
+
+	var f Float
+	require.NoError(t, f.GobDecode(legacyGobEncode(1.5, true)))
+	assert.Equal(t, FloatFrom(1.5), f)
+
+	var b Bool
+	require.NoError(t, b.GobDecode(legacyGobEncode(true, true)))
+	assert.Equal(t, BoolFrom(true), b)
+	require.NoError(t, b.GobDecode(legacyGobEncode(false, false)))
+	assert.Equal(t, NewBool(false, false), b)
+
+	var s String
+	require.NoError(t, s.GobDecode(legacyGobEncode("", true)))
+	assert.Equal(t, NewString("", true), s)
+	require.NoError(t, s.GobDecode(legacyGobEncode("hello", true)))
+	assert.Equal(t, StringFrom("hello"), s)
+
+	var ti Time
+	require.NoError(t, ti.GobDecode(legacyGobEncode(testTime, true)))
+	assert.Equal(t, TimeFrom(testTime), ti)
+	require.NoError(t, ti.GobDecode(legacyGobEncode(time.Time{}, false)))
+	assert.Equal(t, NewTime(time.Time{}, false), ti)
+}
+
+func BenchmarkGobEncode(b *testing.B) {
+	benchmarks := []struct {
+		name   string
+		value  interface{ GobEncode() ([]byte, error) }
+		legacy func() []byte
+	}{
+		{name: "Int", value: IntFrom(1234567), legacy: func() []byte { return legacyGobEncode(int64(1234567), true) }},
+		{name: "Float", value: FloatFrom(12.5), legacy: func() []byte { return legacyGobEncode(12.5, true) }},
+		{name: "Bool", value: BoolFrom(true), legacy: func() []byte { return legacyGobEncode(true, true) }},
+		{name: "String", value: StringFrom("hello world"), legacy: func() []byte { return legacyGobEncode("hello world", true) }},
+		{name: "Time", value: TimeFrom(testTime), legacy: func() []byte { return legacyGobEncode(testTime, true) }},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name+"/legacy", func(b *testing.B) {
+			var data []byte
+			for i := 0; i < b.N; i++ {
+				data = bm.legacy()
+			}
+			b.ReportMetric(float64(len(data)), "bytes/op")
+		})
+		b.Run(bm.name+"/binary", func(b *testing.B) {
+			var data []byte
+			for i := 0; i < b.N; i++ {
+				data, _ = bm.value.GobEncode()
+			}
+			b.ReportMetric(float64(len(data)), "bytes/op")
+		})
+	}
+}
+
+func BenchmarkGobDecode(b *testing.B) {
+	legacy := legacyGobEncode(int64(1234567), true)
+	current, _ := IntFrom(1234567).GobEncode()
+
+	b.Run("Int/legacy", func(b *testing.B) {
+		var v Int
+		for i := 0; i < b.N; i++ {
+			_ = v.GobDecode(legacy)
+		}
+	})
+	b.Run("Int/binary", func(b *testing.B) {
+		var v Int
+		for i := 0; i < b.N; i++ {
+			_ = v.GobDecode(current)
+		}
+	})
+}
@@ -1,13 +1,15 @@
 package nulled
 
 import (
-	"bytes"
-	"encoding/gob"
+	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
 )
 
 type Bool null.Bool
@@ -34,76 +36,207 @@ func (b Bool) ValueOrZero() bool {
 	return b.Bool
 }
 
+// IsZero reports whether the value is null.
+func (b Bool) IsZero() bool {
+	return !b.Valid
+}
+
+// asValue returns b as the Value it is built on.
+func (b Bool) asValue() Value[bool] {
+	return NewValue(b.Bool, b.Valid)
+}
+
+func boolFromValue(v Value[bool]) Bool {
+	return NewBool(v.V, v.Valid)
+}
+
+// WithPolicy returns the value nulled according to p. No policy option
+// applies to booleans, so only an invalid value is normalized.
+func (b Bool) WithPolicy(p Policy) Bool {
+	return boolFromValue(b.asValue().WithPolicy(p))
+}
+
+func (b *Bool) applyPolicy(p Policy) {
+	*b = b.WithPolicy(p)
+}
+
 func (b Bool) EncodeValues(key string, v *url.Values) error {
+	return b.encodeValues(key, v, DefaultPolicy)
+}
+
+func (b Bool) encodeValues(key string, v *url.Values, p Policy) error {
+	return b.asValue().encodeValues(key, v, p)
+}
+
+func (b Bool) MarshalJSON() ([]byte, error) {
+	return b.marshalJSON(DefaultPolicy)
+}
+
+func (b Bool) marshalJSON(p Policy) ([]byte, error) {
+	return b.asValue().marshalJSON(p)
+}
+
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	return b.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (b *Bool) unmarshalJSON(data []byte, p Policy) error {
+	v := b.asValue()
+	err := v.unmarshalJSON(data, p)
+	*b = boolFromValue(v)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (b Bool) MarshalText() ([]byte, error) {
+	return b.marshalText(DefaultPolicy)
+}
+
+func (b Bool) marshalText(p Policy) ([]byte, error) {
+	return b.asValue().marshalText(p)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Besides true/false it accepts 1/0, which is what EncodeValues writes.
+func (b *Bool) UnmarshalText(text []byte) error {
+	return b.unmarshalText(text, DefaultPolicy)
+}
+
+func (b *Bool) unmarshalText(text []byte, p Policy) error {
+	v := b.asValue()
+	err := v.unmarshalText(text, p)
+	*b = boolFromValue(v)
+	return err
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (b Bool) MarshalYAML() (any, error) {
+	return b.marshalYAML(DefaultPolicy)
+}
+
+func (b Bool) marshalYAML(p Policy) (any, error) {
+	b = b.WithPolicy(p)
 	if !b.Valid {
-		return nil
+		return nil, nil
 	}
+	return b.Bool, nil
+}
 
-	if b.Bool {
-		v.Set(key, "1")
-	} else {
-		v.Set(key, "0")
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (b *Bool) UnmarshalYAML(value *yaml.Node) error {
+	return b.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (b *Bool) unmarshalYAML(value *yaml.Node, p Policy) error {
+	if isYAMLNull(value) {
+		*b = NewBool(false, false)
+		return nil
+	}
+	var v bool
+	if err := value.Decode(&v); err != nil {
+		return err
 	}
+	*b = NewBool(v, true).WithPolicy(p)
 	return nil
 }
 
-func (b Bool) MarshalJSON() ([]byte, error) {
+// MarshalXML implements the xml.Marshaler interface.
+func (b Bool) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalXMLElement(e, start, strconv.FormatBool(b.Bool), b.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (b *Bool) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*b = NewBool(false, false)
+		return nil
+	}
+	return b.UnmarshalText([]byte(strings.TrimSpace(s)))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (b Bool) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
 	if !b.Valid {
-		return []byte("null"), nil
+		return xml.Attr{}, nil
 	}
-	return json.Marshal(b.Bool)
+	return xml.Attr{Name: name, Value: strconv.FormatBool(b.Bool)}, nil
 }
 
-func (b *Bool) UnmarshalJSON(data []byte) error {
-	var bo null.Bool
-	if err := json.Unmarshal(data, &bo); err != nil {
-		b.Valid = false
-		return err
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (b *Bool) UnmarshalXMLAttr(attr xml.Attr) error {
+	return b.UnmarshalText([]byte(strings.TrimSpace(attr.Value)))
+}
+
+// Scan implements the sql.Scanner interface.
+// Besides bool it accepts the integers 0 and 1 (TINYINT(1) columns) and the
+// text spellings 1/0, t/f, true/false, y/n, yes/no and on/off in any case.
+// Blank text is scanned as null.
+func (b *Bool) Scan(value any) error {
+	return b.scan(value, DefaultPolicy)
+}
+
+func (b *Bool) scan(value any, p Policy) error {
+	v := b.asValue()
+	err := v.scan(value, p)
+	*b = boolFromValue(v)
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (b Bool) Value() (driver.Value, error) {
+	return b.value(DefaultPolicy)
+}
+
+func (b Bool) value(p Policy) (driver.Value, error) {
+	return b.asValue().value(p)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (b Bool) MarshalBinary() ([]byte, error) {
+	data := binaryHeader(b.Valid)
+	if !b.Valid {
+		return data, nil
 	}
-	b.Bool = bo.Bool
-	b.Valid = bo.Valid
-	return nil
+	return boolCodec{}.EncodeBinary(data, b.Bool)
 }
 
-// UnmarshalText implements the encoding.TextUnmarshaler interface.
-func (b *Bool) UnmarshalText(text []byte) error {
-	s := string(text)
-	if s == "" || s == "null" {
-		b.Valid = false
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (b *Bool) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*b = NewBool(false, false)
 		return nil
 	}
-	var err error
-	b.Bool, err = strconv.ParseBool(s)
+	x, err := boolCodec{}.DecodeBinary(p)
 	if err != nil {
-		b.Valid = false
 		return err
 	}
-	b.Valid = true
+	*b = NewBool(x, true)
 	return nil
 }
 
+// GobEncode implements the gob.GobEncoder interface.
 func (b Bool) GobEncode() ([]byte, error) {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	err := enc.Encode(b.Bool)
-	if err != nil {
-		return nil, err
-	}
-	err = enc.Encode(b.Valid)
-	if err != nil {
-		return nil, err
-	}
-	return buf.Bytes(), nil
+	return b.MarshalBinary()
 }
 
+// GobDecode implements the gob.GobDecoder interface.
+// It also reads the format written by earlier versions of GobEncode.
 func (b *Bool) GobDecode(data []byte) error {
-	buf := bytes.NewBuffer(data)
-	dec := gob.NewDecoder(buf)
-	err := dec.Decode(&b.Bool)
-	if err != nil {
-		return err
+	if isLegacyGob(data) {
+		return decodeLegacyGob(data, &b.Bool, &b.Valid)
 	}
-	return dec.Decode(&b.Valid)
+	return b.UnmarshalBinary(data)
 }
 
 func (b Bool) NullValue() null.Bool {
@@ -113,3 +246,55 @@ func (b Bool) NullValue() null.Bool {
 	}
 	return null.NewBool(false, false)
 }
+
+// boolCodec is the codec of Bool.
+type boolCodec struct{ DefaultCodec[bool] }
+
+// EncodeValue writes 1 or 0.
+func (boolCodec) EncodeValue(b bool) (string, error) {
+	if b {
+		return "1", nil
+	}
+	return "0", nil
+}
+
+func (boolCodec) DecodeJSON(data []byte) (bool, bool, error) {
+	var b null.Bool
+	if err := json.Unmarshal(data, &b); err != nil {
+		return false, false, err
+	}
+	return b.Bool, b.Valid, nil
+}
+
+func (boolCodec) DecodeText(s string) (bool, bool, error) {
+	if s == "" || s == "null" {
+		return false, false, nil
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, false, err
+	}
+	return b, true, nil
+}
+
+func (boolCodec) Scan(src any) (bool, bool, error) {
+	return toBool(src)
+}
+
+func (boolCodec) Normalize(b bool, p Policy) (bool, bool) {
+	return b, true
+}
+
+func (boolCodec) EncodeBinary(b []byte, v bool) ([]byte, error) {
+	if v {
+		return append(b, 1), nil
+	}
+	return append(b, 0), nil
+}
+
+func (boolCodec) DecodeBinary(data []byte) (bool, error) {
+	if len(data) != 1 || data[0] > 1 {
+		return false, ErrInvalidBinary
+	}
+	return data[0] == 1, nil
+}
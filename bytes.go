@@ -0,0 +1,389 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"strings"
+
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
+)
+
+// BytesEncoding supplies the text form of an EncodedBytes. Implement it on
+// an empty struct to use an encoding of your own.
+type BytesEncoding interface {
+	EncodeBytes(b []byte) string
+	DecodeBytes(s string) ([]byte, error)
+}
+
+// Predefined byte encodings. Base64 decoding accepts both alphabets, with or
+// without padding.
+type (
+	BytesBase64    struct{} // standard base64 with padding, as encoding/json writes []byte
+	BytesBase64URL struct{} // unpadded base64url, safe in URLs and file names
+	BytesHex       struct{} // lower case hex
+)
+
+func (BytesBase64) EncodeBytes(b []byte) string    { return base64.StdEncoding.EncodeToString(b) }
+func (BytesBase64URL) EncodeBytes(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+func (BytesHex) EncodeBytes(b []byte) string       { return hex.EncodeToString(b) }
+
+func (BytesBase64) DecodeBytes(s string) ([]byte, error)    { return decodeBase64(s) }
+func (BytesBase64URL) DecodeBytes(s string) ([]byte, error) { return decodeBase64(s) }
+func (BytesHex) DecodeBytes(s string) ([]byte, error)       { return hex.DecodeString(s) }
+
+// decodeBase64 decodes standard or URL base64, with or without padding.
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	s = strings.NewReplacer("-", "+", "_", "/").Replace(s)
+	return base64.RawStdEncoding.DecodeString(s)
+}
+
+// Bytes is a nullable byte slice, for BLOB and BYTEA columns such as
+// thumbnails, signatures and hashes. Unlike String it keeps empty values
+// apart from null: an empty valid Bytes is written as "" in JSON and as an
+// empty, non-NULL value to the database.
+type Bytes struct {
+	Bytes []byte
+	Valid bool
+}
+
+// NewBytes creates a Bytes. It doesn't copy b.
+func NewBytes(b []byte, valid bool) Bytes {
+	if !valid {
+		return Bytes{}
+	}
+	return Bytes{Bytes: b, Valid: true}
+}
+
+// BytesFrom creates a Bytes that is null if b is nil. An empty, non-nil b is
+// valid.
+func BytesFrom(b []byte) Bytes {
+	return NewBytes(b, b != nil)
+}
+
+// ValueOrZero returns the value, or nil if it is null.
+func (b Bytes) ValueOrZero() []byte {
+	if !b.Valid {
+		return nil
+	}
+	return b.Bytes
+}
+
+// IsZero reports whether the value is null.
+func (b Bytes) IsZero() bool {
+	return !b.Valid
+}
+
+func (b Bytes) EncodeValues(key string, v *url.Values) error {
+	return b.encodeValues(key, v, BytesBase64{})
+}
+
+func (b Bytes) encodeValues(key string, v *url.Values, enc BytesEncoding) error {
+	if !b.Valid {
+		return nil
+	}
+	v.Set(key, enc.EncodeBytes(b.Bytes))
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The value is encoded as a base64 string.
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return b.marshalJSON(BytesBase64{})
+}
+
+func (b Bytes) marshalJSON(enc BytesEncoding) ([]byte, error) {
+	if !b.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(enc.EncodeBytes(b.Bytes))
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts null and base64 strings; "" is a valid empty value.
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	return b.unmarshalJSON(data, BytesBase64{})
+}
+
+func (b *Bytes) unmarshalJSON(data []byte, enc BytesEncoding) error {
+	v, err := decodeLenientJSON(data, "Bytes")
+	if err != nil {
+		*b = Bytes{}
+		return err
+	}
+	switch s := v.(type) {
+	case nil:
+		*b = Bytes{}
+	case string:
+		p, err := enc.DecodeBytes(s)
+		if err != nil {
+			*b = Bytes{}
+			return &ScanError{Src: s, Type: "Bytes", Err: err}
+		}
+		*b = Bytes{Bytes: p, Valid: true}
+	default:
+		*b = Bytes{}
+		return &ScanError{Src: s, Type: "Bytes", Err: ErrUnsupportedType}
+	}
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The value is encoded as base64, a null value as empty text.
+func (b Bytes) MarshalText() ([]byte, error) {
+	return b.marshalText(BytesBase64{})
+}
+
+func (b Bytes) marshalText(enc BytesEncoding) ([]byte, error) {
+	if !b.Valid {
+		return []byte{}, nil
+	}
+	return []byte(enc.EncodeBytes(b.Bytes)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Blank text and "null" are null; use JSON to tell empty values from null.
+func (b *Bytes) UnmarshalText(text []byte) error {
+	return b.unmarshalText(text, BytesBase64{})
+}
+
+func (b *Bytes) unmarshalText(text []byte, enc BytesEncoding) error {
+	s := strings.TrimSpace(string(text))
+	if s == "" || s == "null" {
+		*b = Bytes{}
+		return nil
+	}
+	p, err := enc.DecodeBytes(s)
+	if err != nil {
+		*b = Bytes{}
+		return &ScanError{Src: s, Type: "Bytes", Err: err}
+	}
+	*b = Bytes{Bytes: p, Valid: true}
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// The value is encoded as a base64 string.
+func (b Bytes) MarshalYAML() (any, error) {
+	return b.marshalYAML(BytesBase64{})
+}
+
+func (b Bytes) marshalYAML(enc BytesEncoding) (any, error) {
+	if !b.Valid {
+		return nil, nil
+	}
+	return yamlScalar("!!str", enc.EncodeBytes(b.Bytes)), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// It accepts base64 strings; "" is a valid empty value.
+func (b *Bytes) UnmarshalYAML(value *yaml.Node) error {
+	return b.unmarshalYAML(value, BytesBase64{})
+}
+
+func (b *Bytes) unmarshalYAML(value *yaml.Node, enc BytesEncoding) error {
+	s, valid, err := yamlText(value, "nulled.Bytes")
+	if err != nil || !valid {
+		*b = Bytes{}
+		return err
+	}
+	p, err := enc.DecodeBytes(s)
+	if err != nil {
+		*b = Bytes{}
+		return &ScanError{Src: s, Type: "Bytes", Err: err}
+	}
+	*b = Bytes{Bytes: p, Valid: true}
+	return nil
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (b Bytes) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return b.marshalXML(e, start, BytesBase64{})
+}
+
+func (b Bytes) marshalXML(e *xml.Encoder, start xml.StartElement, enc BytesEncoding) error {
+	return marshalXMLElement(e, start, enc.EncodeBytes(b.Bytes), b.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (b *Bytes) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return b.unmarshalXML(d, start, BytesBase64{})
+}
+
+func (b *Bytes) unmarshalXML(d *xml.Decoder, start xml.StartElement, enc BytesEncoding) error {
+	s, valid, err := unmarshalXMLElement(d, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*b = Bytes{}
+		return nil
+	}
+	return b.unmarshalText([]byte(s), enc)
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (b Bytes) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return b.marshalXMLAttr(name, BytesBase64{})
+}
+
+func (b Bytes) marshalXMLAttr(name xml.Name, enc BytesEncoding) (xml.Attr, error) {
+	if !b.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: enc.EncodeBytes(b.Bytes)}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (b *Bytes) UnmarshalXMLAttr(attr xml.Attr) error {
+	return b.unmarshalText([]byte(attr.Value), BytesBase64{})
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts []byte and string sources. Drivers may reuse a []byte buffer
+// after Scan returns, so it is copied.
+func (b *Bytes) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*b = Bytes{}
+	case []byte:
+		*b = Bytes{Bytes: append([]byte{}, v...), Valid: true}
+	case string:
+		*b = Bytes{Bytes: []byte(v), Valid: true}
+	default:
+		return &ScanError{Src: value, Type: "Bytes", Err: ErrUnsupportedType}
+	}
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// An empty valid value is written as an empty []byte, not NULL.
+func (b Bytes) Value() (driver.Value, error) {
+	if !b.Valid {
+		return nil, nil
+	}
+	if b.Bytes == nil {
+		return []byte{}, nil
+	}
+	return b.Bytes, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (b Bytes) MarshalBinary() ([]byte, error) {
+	data := binaryHeader(b.Valid)
+	if !b.Valid {
+		return data, nil
+	}
+	return append(data, b.Bytes...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (b *Bytes) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*b = Bytes{}
+		return nil
+	}
+	*b = Bytes{Bytes: append([]byte{}, p...), Valid: true}
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (b Bytes) GobEncode() ([]byte, error) {
+	return b.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (b *Bytes) GobDecode(data []byte) error {
+	return b.UnmarshalBinary(data)
+}
+
+// NullValue returns the value as a null.String holding the raw bytes, since
+// null.v4 has no byte slice type.
+func (b Bytes) NullValue() null.String {
+	if b.Valid {
+		return null.StringFrom(string(b.Bytes))
+	}
+	return null.NewString("", false)
+}
+
+// EncodedBytes is a Bytes that is written and read in the E encoding in
+// JSON, text, EncodeValues, YAML and XML, instead of base64. SQL, binary and
+// gob encodings behave like Bytes.
+//
+//	Hash nulled.EncodedBytes[nulled.BytesHex] `json:"hash"`
+type EncodedBytes[E BytesEncoding] struct {
+	Bytes
+}
+
+func (b EncodedBytes[E]) encoding() BytesEncoding {
+	var e E
+	return e
+}
+
+// EncodeValues implements the query.Encoder interface.
+func (b EncodedBytes[E]) EncodeValues(key string, v *url.Values) error {
+	return b.Bytes.encodeValues(key, v, b.encoding())
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b EncodedBytes[E]) MarshalJSON() ([]byte, error) {
+	return b.Bytes.marshalJSON(b.encoding())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *EncodedBytes[E]) UnmarshalJSON(data []byte) error {
+	return b.Bytes.unmarshalJSON(data, b.encoding())
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (b EncodedBytes[E]) MarshalText() ([]byte, error) {
+	return b.Bytes.marshalText(b.encoding())
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Blank text and "null" are null.
+func (b *EncodedBytes[E]) UnmarshalText(text []byte) error {
+	return b.Bytes.unmarshalText(text, b.encoding())
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (b EncodedBytes[E]) MarshalYAML() (any, error) {
+	return b.Bytes.marshalYAML(b.encoding())
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (b *EncodedBytes[E]) UnmarshalYAML(value *yaml.Node) error {
+	return b.Bytes.unmarshalYAML(value, b.encoding())
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (b EncodedBytes[E]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return b.Bytes.marshalXML(e, start, b.encoding())
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+func (b *EncodedBytes[E]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return b.Bytes.unmarshalXML(d, start, b.encoding())
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+func (b EncodedBytes[E]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	return b.Bytes.marshalXMLAttr(name, b.encoding())
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (b *EncodedBytes[E]) UnmarshalXMLAttr(attr xml.Attr) error {
+	return b.Bytes.unmarshalText([]byte(attr.Value), b.encoding())
+}
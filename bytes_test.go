@@ -0,0 +1,241 @@
+package nulled
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
+)
+
+// testSignature encodes as "+/8A" in base64 and "-_8A" in base64url.
+var testSignature = []byte{0xfb, 0xff, 0x00}
+
+func TestBytes_Constructors(t *testing.T) {
+	assert.Equal(t, Bytes{Bytes: testSignature, Valid: true}, BytesFrom(testSignature))
+	assert.False(t, BytesFrom(nil).Valid)
+	assert.True(t, BytesFrom([]byte{}).Valid)
+	assert.False(t, NewBytes(testSignature, false).Valid)
+	assert.Nil(t, NewBytes(testSignature, false).ValueOrZero())
+	assert.Equal(t, testSignature, BytesFrom(testSignature).ValueOrZero())
+	assert.True(t, Bytes{}.IsZero())
+	assert.False(t, BytesFrom([]byte{}).IsZero())
+}
+
+func TestBytes_JSON(t *testing.T) {
+	type upload struct {
+		Signature Bytes `json:"signature"`
+		Thumbnail Bytes `json:"thumbnail"`
+		Hash      Bytes `json:"hash"`
+	}
+
+	in := upload{Signature: BytesFrom(testSignature), Thumbnail: BytesFrom([]byte{})}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"signature":"+/8A","thumbnail":"","hash":null}`, string(data))
+
+	var out upload
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, testSignature, out.Signature.Bytes)
+	assert.True(t, out.Thumbnail.Valid, "empty is not null")
+	assert.Empty(t, out.Thumbnail.Bytes)
+	assert.False(t, out.Hash.Valid)
+
+	var b Bytes
+	require.NoError(t, b.UnmarshalJSON([]byte(`"-_8A"`)), "base64url")
+	assert.Equal(t, testSignature, b.Bytes)
+	require.NoError(t, b.UnmarshalJSON([]byte(`"+_8"`)), "mixed alphabets without padding")
+	assert.Equal(t, []byte{0xfb, 0xff}, b.Bytes)
+	assert.Error(t, b.UnmarshalJSON([]byte(`"!!"`)))
+	assert.False(t, b.Valid)
+	assert.ErrorIs(t, b.UnmarshalJSON([]byte(`12`)), ErrUnsupportedType)
+}
+
+func TestEncodedBytes(t *testing.T) {
+	type upload struct {
+		Signature EncodedBytes[BytesBase64]    `json:"signature"`
+		Thumbnail EncodedBytes[BytesBase64URL] `json:"thumbnail"`
+		Hash      EncodedBytes[BytesHex]       `json:"hash"`
+	}
+	sig := BytesFrom(testSignature)
+	in := upload{Signature: EncodedBytes[BytesBase64]{sig}, Thumbnail: EncodedBytes[BytesBase64URL]{sig}, Hash: EncodedBytes[BytesHex]{sig}}
+
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"signature":"+/8A","thumbnail":"-_8A","hash":"fbff00"}`, string(data))
+	var out upload
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	v := url.Values{}
+	require.NoError(t, in.Signature.EncodeValues("signature", &v))
+	require.NoError(t, in.Thumbnail.EncodeValues("thumbnail", &v))
+	require.NoError(t, in.Hash.EncodeValues("hash", &v))
+	assert.Equal(t, url.Values{"signature": {"+/8A"}, "thumbnail": {"-_8A"}, "hash": {"fbff00"}}, v)
+
+	text, err := in.Hash.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "fbff00", string(text))
+	var hash EncodedBytes[BytesHex]
+	require.NoError(t, hash.UnmarshalText([]byte("FBFF00")))
+	assert.Equal(t, testSignature, hash.Bytes.Bytes)
+	assert.ErrorIs(t, hash.UnmarshalText([]byte("fbf")), hex.ErrLength)
+	assert.False(t, hash.Valid)
+	require.NoError(t, hash.UnmarshalJSON([]byte(`null`)))
+	assert.False(t, hash.Valid)
+
+	// each field is read in its own encoding
+	require.NoError(t, json.Unmarshal([]byte(`{"signature":"deadbeef","hash":"deadbeef"}`), &out))
+	assert.Equal(t, []byte{0x75, 0xe6, 0x9d, 0x6d, 0xe7, 0x9f}, out.Signature.Bytes.Bytes)
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, out.Hash.Bytes.Bytes)
+	var b Bytes
+	require.NoError(t, b.UnmarshalText([]byte("deadbeef")))
+	assert.Equal(t, out.Signature.Bytes, b, "Bytes reads base64")
+}
+
+func TestBytes_Text(t *testing.T) {
+	text, err := Bytes{}.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	b := BytesFrom(testSignature)
+	require.NoError(t, b.UnmarshalText([]byte(" ")))
+	assert.False(t, b.Valid)
+	require.NoError(t, b.UnmarshalText([]byte("+/8A")))
+	assert.Equal(t, testSignature, b.Bytes)
+}
+
+func TestBytes_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, BytesFrom(testSignature).EncodeValues("sig", &v))
+	require.NoError(t, BytesFrom([]byte{}).EncodeValues("empty", &v))
+	require.NoError(t, Bytes{}.EncodeValues("skip", &v))
+	assert.Equal(t, url.Values{"sig": {"+/8A"}, "empty": {""}}, v)
+}
+
+func TestBytes_YAML(t *testing.T) {
+	type upload struct {
+		Signature Bytes                  `yaml:"signature"`
+		Thumbnail Bytes                  `yaml:"thumbnail"`
+		Hash      EncodedBytes[BytesHex] `yaml:"hash"`
+		Preview   Bytes                  `yaml:"preview"`
+	}
+	in := upload{Signature: BytesFrom(testSignature), Thumbnail: BytesFrom([]byte{}), Hash: EncodedBytes[BytesHex]{BytesFrom(testSignature)}}
+	data, err := yaml.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, "signature: +/8A\nthumbnail: \"\"\nhash: fbff00\npreview: null\n", string(data))
+
+	var out upload
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+	assert.True(t, out.Thumbnail.Valid, "empty is not null")
+	assert.Error(t, yaml.Unmarshal([]byte("hash: xyz"), &out))
+	assert.Error(t, yaml.Unmarshal([]byte("signature: [1]"), &out))
+}
+
+func TestBytes_XML(t *testing.T) {
+	type upload struct {
+		XMLName   xml.Name               `xml:"upload"`
+		Signature Bytes                  `xml:"signature,attr"`
+		Hash      EncodedBytes[BytesHex] `xml:"hash"`
+		Preview   Bytes                  `xml:"preview"`
+	}
+	in := upload{Signature: BytesFrom(testSignature), Hash: EncodedBytes[BytesHex]{BytesFrom(testSignature)}}
+	data, err := xml.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `<upload signature="+/8A"><hash>fbff00</hash><preview xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></preview></upload>`, string(data))
+
+	var out upload
+	require.NoError(t, xml.Unmarshal(data, &out))
+	assert.Equal(t, in.Signature, out.Signature)
+	assert.Equal(t, in.Hash, out.Hash)
+	assert.False(t, out.Preview.Valid)
+	assert.Error(t, xml.Unmarshal([]byte(`<upload><hash>xyz</hash></upload>`), &out))
+}
+
+func TestBytes_Scan(t *testing.T) {
+	buf := []byte{1, 2, 3}
+	var b Bytes
+	require.NoError(t, b.Scan(buf))
+	buf[0] = 9
+	assert.Equal(t, []byte{1, 2, 3}, b.Bytes, "driver buffer is copied")
+
+	require.NoError(t, b.Scan("abc"))
+	assert.Equal(t, []byte("abc"), b.Bytes)
+	require.NoError(t, b.Scan([]byte{}))
+	assert.True(t, b.Valid)
+	assert.Empty(t, b.Bytes)
+
+	assert.ErrorIs(t, b.Scan(int64(1)), ErrUnsupportedType)
+	assert.True(t, b.Valid, "unchanged on error")
+	require.NoError(t, b.Scan(nil))
+	assert.False(t, b.Valid)
+}
+
+func TestBytes_Value(t *testing.T) {
+	v, err := BytesFrom(testSignature).Value()
+	require.NoError(t, err)
+	assert.Equal(t, testSignature, v)
+
+	v, err = NewBytes(nil, true).Value()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{}, v)
+
+	v, err = Bytes{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestBytes_SQL(t *testing.T) {
+	db := openTestDB(t)
+
+	_, err := db.Exec("INSERT", BytesFrom(testSignature), BytesFrom([]byte{}), Bytes{})
+	require.NoError(t, err)
+
+	var a, b, c Bytes
+	c = BytesFrom(testSignature)
+	require.NoError(t, db.QueryRow("SELECT").Scan(&a, &b, &c))
+	assert.Equal(t, BytesFrom(testSignature), a)
+	assert.True(t, b.Valid)
+	assert.Empty(t, b.Bytes)
+	assert.False(t, c.Valid)
+}
+
+func TestBytes_Binary(t *testing.T) {
+	data, err := BytesFrom(testSignature).MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 0xfb, 0xff, 0x00}, data)
+
+	var b Bytes
+	require.NoError(t, b.UnmarshalBinary(data))
+	assert.Equal(t, BytesFrom(testSignature), b)
+	data[1] = 0
+	assert.Equal(t, testSignature, b.Bytes, "data is copied")
+
+	data, err = Bytes{}.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0}, data)
+	require.NoError(t, b.UnmarshalBinary(data))
+	assert.False(t, b.Valid)
+	assert.ErrorIs(t, b.UnmarshalBinary(nil), ErrInvalidBinary)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(BytesFrom([]byte{})))
+	b = Bytes{}
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&b))
+	assert.True(t, b.Valid)
+	assert.Empty(t, b.Bytes)
+}
+
+func TestBytes_NullValue(t *testing.T) {
+	assert.Equal(t, null.StringFrom("abc"), BytesFrom([]byte("abc")).NullValue())
+	assert.Equal(t, null.StringFrom(""), BytesFrom([]byte{}).NullValue())
+	assert.Equal(t, null.NewString("", false), Bytes{}.NullValue())
+}
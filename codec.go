@@ -0,0 +1,438 @@
+package nulled
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Codec converts the element of a Value[T] to and from its encodings. Value
+// deals with null itself, so a Codec only ever sees valid values. Methods
+// that decode return false alongside the value for input that means null,
+// such as blank text.
+//
+// To customize a few methods only, embed DefaultCodec[T]:
+//
+//	type centsCodec struct{ nulled.DefaultCodec[Cents] }
+//
+//	func (centsCodec) EncodeText(c Cents) ([]byte, error) { ... }
+//
+//	func init() { nulled.RegisterCodec[Cents](centsCodec{}) }
+type Codec[T any] interface {
+	// Normalize cleans up v under p and reports whether it stays valid.
+	Normalize(v T, p Policy) (T, bool)
+
+	EncodeJSON(v T) ([]byte, error)
+	// DecodeJSON decodes any JSON value except null.
+	DecodeJSON(data []byte) (T, bool, error)
+
+	EncodeText(v T) ([]byte, error)
+	// DecodeText decodes text the Policy has already been applied to.
+	DecodeText(s string) (T, bool, error)
+
+	// EncodeValue returns the url.Values form of v.
+	EncodeValue(v T) (string, error)
+
+	// Scan converts a non-nil value handed over by a database driver.
+	Scan(src any) (T, bool, error)
+	Value(v T) (driver.Value, error)
+
+	// EncodeBinary appends the binary form of v, which follows the validity
+	// byte, to b.
+	EncodeBinary(b []byte, v T) ([]byte, error)
+	DecodeBinary(data []byte) (T, error)
+}
+
+var codecs sync.Map // reflect.Type -> Codec[T]
+
+func init() {
+	RegisterCodec[string](stringCodec{})
+	RegisterCodec[int64](intCodec{})
+	RegisterCodec[float64](floatCodec{})
+	RegisterCodec[bool](boolCodec{})
+	RegisterCodec[time.Time](timeCodec{})
+	RegisterCodec[int32](numberCodec[int32]{name: "Int32"})
+	RegisterCodec[int16](numberCodec[int16]{name: "Int16"})
+	RegisterCodec[int8](numberCodec[int8]{name: "Int8"})
+	RegisterCodec[uint](numberCodec[uint]{name: "Uint"})
+	RegisterCodec[uint64](numberCodec[uint64]{name: "Uint64"})
+	RegisterCodec[uint32](numberCodec[uint32]{name: "Uint32"})
+	RegisterCodec[uint16](numberCodec[uint16]{name: "Uint16"})
+	RegisterCodec[uint8](numberCodec[uint8]{name: "Uint8"})
+	RegisterCodec[float32](numberCodec[float32]{name: "Float32"})
+}
+
+// RegisterCodec makes c the codec of Value[T]. Call it from an init
+// function. It panics if T already has a codec, which is the case for the
+// element types of String, Int, Float, Bool, Time and the sized numbers such
+// as Int32 and Uint64.
+func RegisterCodec[T any](c Codec[T]) {
+	t := reflect.TypeFor[T]()
+	if _, dup := codecs.LoadOrStore(t, c); dup {
+		panic("nulled: RegisterCodec called twice for " + t.String())
+	}
+}
+
+// codecFor returns the codec registered for T, or DefaultCodec[T]. The
+// codecs of String, Int, Float, Bool and Time are returned without the
+// registry lookup, which would cost more than encoding their values.
+func codecFor[T any]() Codec[T] {
+	var c any
+	switch any((*T)(nil)).(type) {
+	case *string:
+		c = stringCodec{}
+	case *int64:
+		c = intCodec{}
+	case *float64:
+		c = floatCodec{}
+	case *bool:
+		c = boolCodec{}
+	case *time.Time:
+		c = timeCodec{}
+	default:
+		if r, ok := codecs.Load(reflect.TypeFor[T]()); ok {
+			return r.(Codec[T])
+		}
+		return DefaultCodec[T]{}
+	}
+	return c.(Codec[T])
+}
+
+// DefaultCodec is the codec of element types without a registered one. It
+// uses the methods T implements, encoding.TextMarshaler, sql.Scanner,
+// driver.Valuer and encoding.BinaryMarshaler with their counterparts, and
+// otherwise handles types whose underlying type is a string, a number or a
+// bool like String, Int, Float and Bool do. JSON goes through encoding/json
+// and binary data of other types through encoding/gob.
+type DefaultCodec[T any] struct{}
+
+func (DefaultCodec[T]) typeName() string {
+	return reflect.TypeFor[T]().String()
+}
+
+// Normalize applies the string options of p to string types and ZeroAsNull
+// to numbers.
+func (DefaultCodec[T]) Normalize(v T, p Policy) (T, bool) {
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		s := p.text(rv.String())
+		if (p.EmptyAsNull && s == "") || (p.WhitespaceAsNull && strings.TrimSpace(s) == "") {
+			var zero T
+			return zero, false
+		}
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if p.ZeroAsNull && rv.IsZero() {
+			var zero T
+			return zero, false
+		}
+	}
+	return v, true
+}
+
+func (DefaultCodec[T]) EncodeJSON(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (DefaultCodec[T]) DecodeJSON(data []byte) (T, bool, error) {
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, false, err
+	}
+	return v, true, nil
+}
+
+func (c DefaultCodec[T]) EncodeText(v T) ([]byte, error) {
+	if m, ok := any(v).(encoding.TextMarshaler); ok {
+		return m.MarshalText()
+	}
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		return []byte(rv.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(nil, rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(nil, rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(nil, rv.Float(), 'f', -1, rv.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.AppendBool(nil, rv.Bool()), nil
+	}
+	return nil, fmt.Errorf("nulled: %s has no text form", c.typeName())
+}
+
+// DecodeText treats blank text and "null" as null, except for string
+// types.
+func (c DefaultCodec[T]) DecodeText(s string) (T, bool, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	if rv.Kind() != reflect.String && (strings.TrimSpace(s) == "" || s == "null") {
+		return v, false, nil
+	}
+	fail := func(err error) (T, bool, error) {
+		var zero T
+		return zero, false, &ScanError{Src: s, Type: c.typeName(), Err: err}
+	}
+	if u, ok := any(&v).(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return v, false, err
+		}
+		return v, true, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(s), 10, rv.Type().Bits())
+		if err != nil {
+			return fail(err.(*strconv.NumError).Err)
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(strings.TrimSpace(s), 10, rv.Type().Bits())
+		if err != nil {
+			return fail(err.(*strconv.NumError).Err)
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), rv.Type().Bits())
+		if err != nil {
+			return fail(err.(*strconv.NumError).Err)
+		}
+		rv.SetFloat(f)
+	case reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return fail(err)
+		}
+		rv.SetBool(b)
+	default:
+		return fail(ErrUnsupportedType)
+	}
+	return v, true, nil
+}
+
+func (c DefaultCodec[T]) EncodeValue(v T) (string, error) {
+	text, err := c.EncodeText(v)
+	return string(text), err
+}
+
+// Scan accepts what the sql.Scanner of T accepts, values of type T itself,
+// and for string, number and bool types the same values as String, Int,
+// Float and Bool. Other text is decoded with DecodeText.
+func (c DefaultCodec[T]) Scan(src any) (T, bool, error) {
+	var v T
+	if s, ok := any(&v).(sql.Scanner); ok {
+		if err := s.Scan(src); err != nil {
+			return v, false, err
+		}
+		return v, true, nil
+	}
+	if x, ok := src.(T); ok {
+		return x, true, nil
+	}
+	fail := func(err error) (T, bool, error) {
+		var zero T
+		return zero, false, &ScanError{Src: src, Type: c.typeName(), Err: err}
+	}
+	// the helpers report their own type name
+	retype := func(err error) (T, bool, error) {
+		if e, ok := err.(*ScanError); ok {
+			return fail(e.Err)
+		}
+		return fail(err)
+	}
+
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		var ns sql.NullString
+		if err := ns.Scan(src); err != nil {
+			return fail(ErrUnsupportedType)
+		}
+		rv.SetString(ns.String)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, valid, err := toInt64(src)
+		if err != nil || !valid {
+			return retype(err)
+		}
+		if rv.OverflowInt(n) {
+			return fail(strconv.ErrRange)
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// text beyond the int64 range, e.g. from a BIGINT UNSIGNED column
+		if s, ok := toText(src); ok {
+			if u, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64); err == nil {
+				src = u
+			}
+		}
+		if u, ok := src.(uint64); ok {
+			if rv.OverflowUint(u) {
+				return fail(strconv.ErrRange)
+			}
+			rv.SetUint(u)
+			break
+		}
+		n, valid, err := toInt64(src)
+		if err != nil || !valid {
+			return retype(err)
+		}
+		if n < 0 || rv.OverflowUint(uint64(n)) {
+			return fail(strconv.ErrRange)
+		}
+		rv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		f, valid, err := toFloat64(src)
+		if err != nil || !valid {
+			return retype(err)
+		}
+		if rv.OverflowFloat(f) {
+			return fail(strconv.ErrRange)
+		}
+		rv.SetFloat(f)
+	case reflect.Bool:
+		b, valid, err := toBool(src)
+		if err != nil || !valid {
+			return retype(err)
+		}
+		rv.SetBool(b)
+	default:
+		if s, ok := toText(src); ok {
+			return c.DecodeText(s)
+		}
+		return fail(ErrUnsupportedType)
+	}
+	return v, true, nil
+}
+
+// Value returns what the driver.Valuer of T returns, and otherwise the
+// underlying string, int64, float64, bool or []byte. Unsigned integers beyond
+// the int64 range and other types are written as their text form.
+func (c DefaultCodec[T]) Value(v T) (driver.Value, error) {
+	if vr, ok := any(v).(driver.Valuer); ok {
+		return vr.Value()
+	}
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return strconv.FormatUint(rv.Uint(), 10), nil
+		}
+		return int64(rv.Uint()), nil
+	case reflect.Float32:
+		return widenFloat32(float32(rv.Float())), nil
+	case reflect.Float64:
+		return rv.Float(), nil
+	case reflect.Bool:
+		return rv.Bool(), nil
+	}
+	if driver.IsValue(v) {
+		return v, nil
+	}
+	text, err := c.EncodeText(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// EncodeBinary uses the encoding.BinaryMarshaler of T, the formats of Int,
+// Float, Bool and String for their kinds, and gob for anything else.
+func (c DefaultCodec[T]) EncodeBinary(b []byte, v T) ([]byte, error) {
+	if m, ok := any(v).(encoding.BinaryMarshaler); ok {
+		data, err := m.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return append(b, data...), nil
+	}
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		b = binary.AppendUvarint(b, uint64(rv.Len()))
+		return append(b, rv.String()...), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(b, rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return binary.AppendUvarint(b, rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(rv.Float())), nil
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	}
+	buf := bytes.NewBuffer(b)
+	if err := gob.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c DefaultCodec[T]) DecodeBinary(data []byte) (T, error) {
+	var v T
+	if u, ok := any(&v).(encoding.BinaryUnmarshaler); ok {
+		if err := u.UnmarshalBinary(data); err != nil {
+			return v, ErrInvalidBinary
+		}
+		return v, nil
+	}
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		n, size := binary.Uvarint(data)
+		if size <= 0 || uint64(len(data)-size) != n {
+			return v, ErrInvalidBinary
+		}
+		rv.SetString(string(data[size:]))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, size := binary.Varint(data)
+		if size <= 0 || size != len(data) || rv.OverflowInt(n) {
+			return v, ErrInvalidBinary
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, size := binary.Uvarint(data)
+		if size <= 0 || size != len(data) || rv.OverflowUint(n) {
+			return v, ErrInvalidBinary
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		if len(data) != 8 {
+			return v, ErrInvalidBinary
+		}
+		rv.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data)))
+	case reflect.Bool:
+		if len(data) != 1 || data[0] > 1 {
+			return v, ErrInvalidBinary
+		}
+		rv.SetBool(data[0] == 1)
+	default:
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&v); err != nil {
+			return v, ErrInvalidBinary
+		}
+	}
+	return v, nil
+}
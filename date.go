@@ -0,0 +1,360 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DateLayout is the layout Date is written and read with.
+const DateLayout = time.DateOnly
+
+// compactDateLayout is the ISO 8601 basic form of DateLayout.
+const compactDateLayout = "20060102"
+
+// Date is a nullable civil date: a year, month and day without time of day
+// or zone, for columns and fields such as birthdays that a time.Time at
+// midnight would shift across days.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+	Valid bool
+}
+
+// NewDate creates a Date. Out of range months and days are normalized the
+// same way as time.Date does, e.g. October 32 becomes November 1.
+func NewDate(year int, month time.Month, day int, valid bool) Date {
+	if !valid {
+		return Date{}
+	}
+	return dateOf(time.Date(year, month, day, 0, 0, 0, 0, time.UTC))
+}
+
+// DateFrom creates a valid Date.
+func DateFrom(year int, month time.Month, day int) Date {
+	return NewDate(year, month, day, true)
+}
+
+// DateFromTime returns the date of t in loc, or in the location t carries
+// when loc is nil. A null Time gives a null Date.
+func DateFromTime(t Time, loc *time.Location) Date {
+	if t.isNull() {
+		return Date{}
+	}
+	if loc != nil {
+		return dateOf(t.Time.In(loc))
+	}
+	return dateOf(t.Time)
+}
+
+// dateOf returns the date of t in the location t carries.
+func dateOf(t time.Time) Date {
+	y, m, d := t.Date()
+	return Date{Year: y, Month: m, Day: d, Valid: true}
+}
+
+// TimeIn returns midnight at the start of the date in loc, or a null Time
+// if the date is null. Like TimeOfDay.On, it isn't reduced to TimePrecision.
+func (d Date) TimeIn(loc *time.Location) Time {
+	if !d.Valid {
+		return NewTime(time.Time{}, false)
+	}
+	return exactTime(time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, loc), true)
+}
+
+// utc returns the date as midnight UTC, which date arithmetic is done on.
+func (d Date) utc() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// IsZero reports whether the value is null.
+func (d Date) IsZero() bool {
+	return !d.Valid
+}
+
+// String returns the date as "2006-01-02", or "" if it is null.
+func (d Date) String() string {
+	if !d.Valid {
+		return ""
+	}
+	return d.utc().Format(DateLayout)
+}
+
+// AddDays returns the date n days later, or earlier for a negative n.
+// A null date stays null.
+func (d Date) AddDays(n int) Date {
+	return d.AddDate(0, 0, n)
+}
+
+// AddDate returns the date with the given years, months and days added,
+// normalized like time.Time.AddDate. A null date stays null.
+func (d Date) AddDate(years, months, days int) Date {
+	if !d.Valid {
+		return Date{}
+	}
+	return dateOf(d.utc().AddDate(years, months, days))
+}
+
+// DaysSince returns the number of days from u to d, negative if d is before
+// u. It returns 0 if either date is null.
+func (d Date) DaysSince(u Date) int {
+	if !d.Valid || !u.Valid {
+		return 0
+	}
+	// Unix seconds, unlike a time.Duration, don't overflow for distant dates
+	return int((d.utc().Unix() - u.utc().Unix()) / (24 * 60 * 60))
+}
+
+// Compare returns -1 if d is before u, +1 if d is after u and 0 if they are
+// equal. A null date is before every valid date.
+func (d Date) Compare(u Date) int {
+	switch {
+	case !d.Valid && !u.Valid:
+		return 0
+	case !d.Valid:
+		return -1
+	case !u.Valid:
+		return 1
+	}
+	return d.utc().Compare(u.utc())
+}
+
+// Before reports whether d is before u.
+func (d Date) Before(u Date) bool {
+	return d.Compare(u) < 0
+}
+
+// After reports whether d is after u.
+func (d Date) After(u Date) bool {
+	return d.Compare(u) > 0
+}
+
+// parseDate parses "2006-01-02" or "20060102". Other text is parsed with
+// TimeLayouts and its date is taken in the location it carries, so DATETIME
+// text is accepted too. Unlike Time, bare numbers aren't read as Unix
+// timestamps. Blank text and MySQL zero dates are null.
+func parseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || isZeroDate(s) {
+		return Date{}, nil
+	}
+	for _, layout := range []string{DateLayout, compactDateLayout} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return dateOf(t), nil
+		}
+	}
+	t, ok := parseLayouts(s)
+	if !ok {
+		return Date{}, strconv.ErrSyntax
+	}
+	if t.IsZero() {
+		return Date{}, nil
+	}
+	return dateOf(t), nil
+}
+
+func (d Date) EncodeValues(key string, v *url.Values) error {
+	if !d.Valid {
+		return nil
+	}
+	v.Set(key, d.String())
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts null and strings understood by UnmarshalText.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		*d = Date{}
+		return err
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Empty text and "null" are null.
+func (d *Date) UnmarshalText(text []byte) error {
+	return d.unmarshalText(text, DefaultPolicy)
+}
+
+func (d *Date) unmarshalText(text []byte, p Policy) error {
+	s := p.text(string(text))
+	if s == "null" {
+		*d = Date{}
+		return nil
+	}
+	v, err := parseDate(s)
+	if err != nil {
+		*d = Date{}
+		return &ScanError{Src: s, Type: "Date", Err: err}
+	}
+	*d = v
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// A valid date is written as a plain "2006-01-02" timestamp.
+func (d Date) MarshalYAML() (any, error) {
+	return d.marshalYAML(DefaultPolicy)
+}
+
+func (d Date) marshalYAML(Policy) (any, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	return yamlScalar("!!timestamp", d.String()), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The value is parsed the same way as UnmarshalText.
+func (d *Date) UnmarshalYAML(value *yaml.Node) error {
+	return d.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (d *Date) unmarshalYAML(value *yaml.Node, p Policy) error {
+	s, valid, err := yamlText(value, "nulled.Date")
+	if err != nil || !valid {
+		*d = Date{}
+		return err
+	}
+	return d.unmarshalText([]byte(s), p)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (d Date) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalXMLElement(e, start, d.String(), d.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (d *Date) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(dec, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*d = Date{}
+		return nil
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (d Date) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if !d.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: d.String()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (d *Date) UnmarshalXMLAttr(attr xml.Attr) error {
+	return d.UnmarshalText([]byte(attr.Value))
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts time.Time, whose date is taken in the location it carries, and
+// text in string or []byte form. NULL, the zero time and MySQL zero dates
+// are scanned as null.
+func (d *Date) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = DateFromTime(exactTime(v, true), nil)
+		return nil
+	}
+
+	s, ok := toText(value)
+	if !ok {
+		return &ScanError{Src: value, Type: "Date", Err: ErrUnsupportedType}
+	}
+	v, err := parseDate(s)
+	if err != nil {
+		return &ScanError{Src: value, Type: "Date", Err: err}
+	}
+	*d = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// A valid date is written as "2006-01-02" text, which DATE columns accept
+// without any zone conversion.
+func (d Date) Value() (driver.Value, error) {
+	if !d.Valid {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (d Date) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(d.Valid)
+	if !d.Valid {
+		return b, nil
+	}
+	b = binary.AppendVarint(b, int64(d.Year))
+	return append(b, byte(d.Month), byte(d.Day)), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (d *Date) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*d = Date{}
+		return nil
+	}
+	year, size := binary.Varint(p)
+	if size <= 0 || len(p) != size+2 {
+		return ErrInvalidBinary
+	}
+	month, day := time.Month(p[size]), int(p[size+1])
+	if month < time.January || month > time.December || day < 1 ||
+		time.Date(int(year), month, day, 0, 0, 0, 0, time.UTC).Day() != day {
+		return ErrInvalidBinary
+	}
+	*d = Date{Year: int(year), Month: month, Day: day, Valid: true}
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (d Date) GobEncode() ([]byte, error) {
+	return d.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (d *Date) GobDecode(data []byte) error {
+	return d.UnmarshalBinary(data)
+}
@@ -0,0 +1,217 @@
+package nulled
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var testDate = DateFrom(2023, time.October, 27)
+
+func TestDate_New(t *testing.T) {
+	assert.Equal(t, Date{Year: 2023, Month: time.October, Day: 27, Valid: true}, testDate)
+	assert.Equal(t, DateFrom(2023, time.November, 1), DateFrom(2023, time.October, 32))
+	assert.Equal(t, Date{}, NewDate(2023, time.October, 27, false))
+	assert.True(t, Date{}.IsZero())
+	assert.Equal(t, "2023-10-27", testDate.String())
+	assert.Equal(t, "", Date{}.String())
+}
+
+func TestDate_Time(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*60*60)
+
+	// midnight in Shanghai is the previous day in UTC
+	ti := testDate.TimeIn(shanghai)
+	assert.Equal(t, time.Date(2023, 10, 27, 0, 0, 0, 0, shanghai), ti.Time)
+	assert.Equal(t, testDate, DateFromTime(ti, nil))
+	assert.Equal(t, DateFrom(2023, time.October, 26), DateFromTime(ti, time.UTC))
+	assert.Equal(t, testDate, DateFromTime(TimeFrom(time.Date(2023, 10, 26, 16, 0, 0, 0, time.UTC)), shanghai))
+
+	assert.False(t, Date{}.TimeIn(time.UTC).Valid)
+	assert.False(t, DateFromTime(NewTime(time.Time{}, false), time.UTC).Valid)
+}
+
+func TestDate_Arithmetic(t *testing.T) {
+	assert.Equal(t, DateFrom(2023, time.November, 3), testDate.AddDays(7))
+	assert.Equal(t, DateFrom(2024, time.February, 29), DateFrom(2024, time.February, 28).AddDays(1))
+	assert.Equal(t, DateFrom(2024, time.March, 2), DateFrom(2023, time.December, 31).AddDate(0, 2, 0))
+	assert.Equal(t, Date{}, Date{}.AddDays(1))
+
+	assert.Equal(t, 366, DateFrom(2025, time.January, 1).DaysSince(DateFrom(2024, time.January, 1)))
+	assert.Equal(t, -7, testDate.DaysSince(testDate.AddDays(7)))
+	assert.Equal(t, 365*1000+243, DateFrom(3000, time.January, 1).DaysSince(DateFrom(2000, time.January, 1)))
+	assert.Equal(t, 0, testDate.DaysSince(Date{}))
+
+	assert.True(t, testDate.Before(testDate.AddDays(1)))
+	assert.True(t, testDate.After(Date{}))
+	assert.Equal(t, 0, testDate.Compare(DateFrom(2023, time.October, 27)))
+}
+
+func TestDate_JSON(t *testing.T) {
+	type person struct {
+		Birthday Date `json:"birthday"`
+		Hired    Date `json:"hired"`
+	}
+	in := person{Birthday: testDate}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"birthday":"2023-10-27","hired":null}`, string(data))
+
+	var out person
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"birthday":"","hired":"2023-10-27T23:30:00-05:00"}`), &out))
+	assert.Equal(t, person{Hired: testDate}, out)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"birthday":"27/10/2023"}`), &out))
+	assert.Error(t, json.Unmarshal([]byte(`{"birthday":20231027}`), &out))
+	require.NoError(t, json.Unmarshal([]byte(`{"birthday":"20231027"}`), &out))
+	assert.Equal(t, testDate, out.Birthday)
+	assert.Error(t, json.Unmarshal([]byte(`{"birthday":"1"}`), &out))
+}
+
+func TestDate_Text(t *testing.T) {
+	text, err := testDate.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "2023-10-27", string(text))
+
+	var d Date
+	require.NoError(t, d.UnmarshalText([]byte(" 2023-10-27 ")))
+	assert.Equal(t, testDate, d)
+	require.NoError(t, d.UnmarshalText([]byte("null")))
+	assert.Equal(t, Date{}, d)
+	require.NoError(t, d.UnmarshalText([]byte("0000-00-00")))
+	assert.Equal(t, Date{}, d)
+	assert.Error(t, d.UnmarshalText([]byte("2023-13-01")))
+
+	require.NoError(t, d.UnmarshalText([]byte("20231027")))
+	assert.Equal(t, testDate, d)
+	assert.Error(t, d.UnmarshalText([]byte("1")), "not a Unix timestamp")
+	assert.Equal(t, Date{}, d)
+	assert.Error(t, d.UnmarshalText([]byte("1698400800")))
+}
+
+func TestDate_YAML(t *testing.T) {
+	type person struct {
+		Birthday Date `yaml:"birthday"`
+		Hired    Date `yaml:"hired"`
+	}
+	data, err := yaml.Marshal(person{Birthday: testDate})
+	require.NoError(t, err)
+	assert.Equal(t, "birthday: 2023-10-27\nhired: null\n", string(data))
+
+	var out person
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, person{Birthday: testDate}, out)
+	require.NoError(t, yaml.Unmarshal([]byte("birthday: \"20231027\"\nhired: 2023-10-27T23:30:00-05:00\n"), &out))
+	assert.Equal(t, person{Birthday: testDate, Hired: testDate}, out)
+	assert.Error(t, yaml.Unmarshal([]byte("birthday: [2023]"), &out))
+	assert.Error(t, yaml.Unmarshal([]byte("birthday: 27/10/2023"), &out))
+}
+
+func TestDate_XML(t *testing.T) {
+	type person struct {
+		XMLName  xml.Name `xml:"person"`
+		Birthday Date     `xml:"birthday,attr"`
+		Hired    Date     `xml:"hired"`
+	}
+	data, err := xml.Marshal(person{Birthday: testDate})
+	require.NoError(t, err)
+	assert.Equal(t, `<person birthday="2023-10-27"><hired xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></hired></person>`, string(data))
+
+	var out person
+	require.NoError(t, xml.Unmarshal(data, &out))
+	assert.Equal(t, person{XMLName: xml.Name{Local: "person"}, Birthday: testDate}, out)
+	require.NoError(t, xml.Unmarshal([]byte(`<person><hired> 2023-10-27 </hired></person>`), &out))
+	assert.Equal(t, testDate, out.Hired)
+	assert.Error(t, xml.Unmarshal([]byte(`<person birthday="x"></person>`), &out))
+}
+
+func TestDate_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, testDate.EncodeValues("a", &v))
+	require.NoError(t, Date{}.EncodeValues("b", &v))
+	assert.Equal(t, url.Values{"a": {"2023-10-27"}}, v)
+}
+
+func TestDate_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Date
+		wantErr bool
+	}{
+		{name: "nil", src: nil, want: Date{}},
+		{name: "time", src: time.Date(2023, 10, 27, 0, 0, 0, 0, time.FixedZone("CST", 8*60*60)), want: testDate},
+		{name: "zero time", src: time.Time{}, want: Date{}},
+		{name: "string", src: "2023-10-27", want: testDate},
+		{name: "bytes", src: []byte("2023-10-27"), want: testDate},
+		{name: "datetime text", src: "2023-10-27 00:00:00", want: testDate},
+		{name: "zero date", src: []byte("0000-00-00"), want: Date{}},
+		{name: "invalid", src: "abc", wantErr: true},
+		{name: "unsupported", src: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Date
+			err := d.Scan(tt.src)
+			if tt.wantErr {
+				var scanErr *ScanError
+				assert.ErrorAs(t, err, &scanErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func TestDate_SQL(t *testing.T) {
+	v, err := testDate.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "2023-10-27", v)
+	v, err = Date{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	db := openTestDB(t)
+	_, err = db.Exec("INSERT", testDate, Date{})
+	require.NoError(t, err)
+	var a, b Date
+	require.NoError(t, db.QueryRow("SELECT").Scan(&a, &b))
+	assert.Equal(t, testDate, a)
+	assert.Equal(t, Date{}, b)
+}
+
+func TestDate_Binary(t *testing.T) {
+	for _, d := range []Date{testDate, DateFrom(2024, time.February, 29), DateFrom(-50, time.January, 1), {}} {
+		data, err := d.MarshalBinary()
+		require.NoError(t, err)
+		var out Date
+		require.NoError(t, out.UnmarshalBinary(data))
+		assert.Equal(t, d, out)
+	}
+
+	var d Date
+	for _, md := range [][2]byte{{2, 31}, {2, 29}, {4, 31}, {13, 1}, {1, 0}} {
+		data := append(binaryHeader(true), 0xce, 0x1f, md[0], md[1]) // 2023
+		assert.ErrorIs(t, d.UnmarshalBinary(data), ErrInvalidBinary, "%d-%d", md[0], md[1])
+	}
+}
+
+func TestDate_Gob(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(testDate))
+	var d Date
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&d))
+	assert.Equal(t, testDate, d)
+}
@@ -0,0 +1,580 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"math"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecimalJSONString makes MarshalJSON write decimals as JSON strings instead
+// of numbers, for clients that would read numbers into a float64, such as
+// JavaScript. UnmarshalJSON accepts both either way.
+var DecimalJSONString = false
+
+// maxDecimalScale bounds the scale of parsed decimals, and the number of
+// zeros a positive exponent adds, so that text like "1e999999999" can't
+// exhaust memory. It is the largest scale of a PostgreSQL NUMERIC.
+const maxDecimalScale = 16383
+
+// Decimal is a nullable exact decimal number, an arbitrary precision integer
+// scaled by a power of ten, for money and NUMERIC or DECIMAL columns. Its
+// scale is the number of digits after the decimal point, so 19.90 has the
+// unscaled value 1990 and scale 2, and is kept by every encoding except
+// EncodeValues. Decimals are never converted through float64.
+//
+// A Decimal is immutable; the arithmetic methods return new values.
+type Decimal struct {
+	coef  *big.Int // nil for zero, never modified once set
+	scale int32
+	Valid bool
+}
+
+// RoundingMode tells Quantize how to drop digits.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest value, and halfway values away from
+	// zero: 2.5 becomes 3 and -2.5 becomes -3. It is the usual commercial
+	// rounding.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest value, and halfway values to the
+	// even neighbour: 2.5 becomes 2 and 3.5 becomes 4. It is also known as
+	// banker's rounding.
+	RoundHalfEven
+	// RoundHalfDown rounds to the nearest value, and halfway values towards
+	// zero: 2.5 becomes 2 and -2.5 becomes -2.
+	RoundHalfDown
+	// RoundDown truncates towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+	// RoundCeiling rounds towards positive infinity.
+	RoundCeiling
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+)
+
+var bigTen = big.NewInt(10)
+
+// pow10 returns 10**n.
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// NewDecimal creates a Decimal worth unscaled * 10**-scale. A negative scale
+// is applied to unscaled, so the result always has a scale of at least 0.
+// unscaled is copied, and nil is zero.
+func NewDecimal(unscaled *big.Int, scale int32, valid bool) Decimal {
+	if !valid {
+		return Decimal{}
+	}
+	coef := new(big.Int)
+	if unscaled != nil {
+		coef.Set(unscaled)
+	}
+	if scale < 0 {
+		coef.Mul(coef, pow10(-scale))
+		scale = 0
+	}
+	return Decimal{coef: coef, scale: scale, Valid: true}
+}
+
+// DecimalFrom creates a Decimal worth unscaled * 10**-scale under
+// DefaultPolicy, e.g. DecimalFrom(1990, 2) is 19.90.
+func DecimalFrom(unscaled int64, scale int32) Decimal {
+	return NewDecimal(big.NewInt(unscaled), scale, true).WithPolicy(DefaultPolicy)
+}
+
+// ParseDecimal parses a decimal number such as "19.90", "-0.5" or "1.5e3".
+// Blank text is null. Errors are ScanErrors wrapping strconv.ErrSyntax, or
+// strconv.ErrRange for exponents that would give more than 16383 digits.
+func ParseDecimal(s string) (Decimal, error) {
+	d, err := parseDecimal(strings.TrimSpace(s))
+	if err != nil {
+		return Decimal{}, &ScanError{Src: s, Type: "Decimal", Err: err}
+	}
+	return d, nil
+}
+
+// parseDecimal parses trimmed text. Blank text is null.
+func parseDecimal(s string) (Decimal, error) {
+	if s == "" {
+		return Decimal{}, nil
+	}
+	mantissa, exp := s, int64(0)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		var err error
+		mantissa = s[:i]
+		exp, err = strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, err.(*strconv.NumError).Err
+		}
+	}
+
+	sign := ""
+	if mantissa != "" && (mantissa[0] == '-' || mantissa[0] == '+') {
+		sign, mantissa = mantissa[:1], mantissa[1:]
+	}
+	whole, frac, _ := strings.Cut(mantissa, ".")
+	digits := whole + frac
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return Decimal{}, strconv.ErrSyntax
+	}
+
+	scale := int64(len(frac)) - exp
+	if scale > maxDecimalScale || scale < -maxDecimalScale {
+		return Decimal{}, strconv.ErrRange
+	}
+	coef, _ := new(big.Int).SetString(sign+digits, 10)
+	return NewDecimal(coef, int32(scale), true), nil
+}
+
+// int returns the unscaled value. It must not be modified.
+func (d Decimal) int() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return d.coef
+}
+
+// Unscaled returns a copy of the unscaled value, or nil if d is null.
+func (d Decimal) Unscaled() *big.Int {
+	if !d.Valid {
+		return nil
+	}
+	return new(big.Int).Set(d.int())
+}
+
+// Scale returns the number of digits after the decimal point.
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// IsZero reports whether the value is null.
+func (d Decimal) IsZero() bool {
+	return !d.Valid
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d, and 0 if it is null.
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// String returns the value in plain notation with all the digits of its
+// scale, e.g. "19.90" or "-0.05", or "" if it is null.
+func (d Decimal) String() string {
+	if !d.Valid {
+		return ""
+	}
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// canonical returns the value without trailing zeros after the decimal
+// point, so that equal values give the same text.
+func (d Decimal) canonical() string {
+	s := d.String()
+	if d.scale > 0 {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// rescale returns the unscaled values of d and e at their common scale.
+func (d Decimal) rescale(e Decimal) (x, y *big.Int, scale int32) {
+	x, y, scale = d.int(), e.int(), d.scale
+	switch {
+	case d.scale < e.scale:
+		x, scale = new(big.Int).Mul(x, pow10(e.scale-d.scale)), e.scale
+	case d.scale > e.scale:
+		y = new(big.Int).Mul(y, pow10(d.scale-e.scale))
+	}
+	return x, y, scale
+}
+
+// Add returns d+e at the larger of their scales. It is null if either is
+// null.
+func (d Decimal) Add(e Decimal) Decimal {
+	if !d.Valid || !e.Valid {
+		return Decimal{}
+	}
+	x, y, scale := d.rescale(e)
+	return Decimal{coef: new(big.Int).Add(x, y), scale: scale, Valid: true}
+}
+
+// Sub returns d-e at the larger of their scales. It is null if either is
+// null.
+func (d Decimal) Sub(e Decimal) Decimal {
+	if !d.Valid || !e.Valid {
+		return Decimal{}
+	}
+	x, y, scale := d.rescale(e)
+	return Decimal{coef: new(big.Int).Sub(x, y), scale: scale, Valid: true}
+}
+
+// Mul returns d*e exactly, at the sum of their scales. Use Quantize to
+// round the result. It is null if either is null.
+func (d Decimal) Mul(e Decimal) Decimal {
+	if !d.Valid || !e.Valid {
+		return Decimal{}
+	}
+	return Decimal{coef: new(big.Int).Mul(d.int(), e.int()), scale: d.scale + e.scale, Valid: true}
+}
+
+// Quantize returns d with exactly scale digits after the decimal point,
+// rounded with mode if digits have to be dropped. A negative scale is
+// treated as 0. A null value stays null.
+//
+//	price.Mul(rate).Quantize(2, nulled.RoundHalfEven)
+func (d Decimal) Quantize(scale int32, mode RoundingMode) Decimal {
+	if !d.Valid {
+		return Decimal{}
+	}
+	scale = max(scale, 0)
+	if scale >= d.scale {
+		return Decimal{coef: new(big.Int).Mul(d.int(), pow10(scale-d.scale)), scale: scale, Valid: true}
+	}
+
+	divisor := pow10(d.scale - scale)
+	q, r := new(big.Int).QuoRem(d.int(), divisor, new(big.Int))
+	if r.Sign() != 0 {
+		sign := d.Sign()
+		// compare the dropped digits with one half
+		twice := new(big.Int).Abs(r)
+		half := twice.Lsh(twice, 1).Cmp(divisor)
+		var away bool
+		switch mode {
+		case RoundHalfUp:
+			away = half >= 0
+		case RoundHalfEven:
+			away = half > 0 || (half == 0 && q.Bit(0) == 1)
+		case RoundHalfDown:
+			away = half > 0
+		case RoundUp:
+			away = true
+		case RoundCeiling:
+			away = sign > 0
+		case RoundFloor:
+			away = sign < 0
+		}
+		if away {
+			q.Add(q, big.NewInt(int64(sign)))
+		}
+	}
+	return Decimal{coef: q, scale: scale, Valid: true}
+}
+
+// Compare returns -1 if d is less than e, +1 if d is greater than e and 0
+// if they are equal, whatever their scales. A null value is less than every
+// valid value.
+func (d Decimal) Compare(e Decimal) int {
+	switch {
+	case !d.Valid && !e.Valid:
+		return 0
+	case !d.Valid:
+		return -1
+	case !e.Valid:
+		return 1
+	}
+	x, y, _ := d.rescale(e)
+	return x.Cmp(y)
+}
+
+// WithPolicy returns the value nulled according to p. ZeroAsNull nulls zero
+// at any scale, such as 0.00.
+func (d Decimal) WithPolicy(p Policy) Decimal {
+	if !d.Valid || (p.ZeroAsNull && d.Sign() == 0) {
+		return Decimal{}
+	}
+	return d
+}
+
+func (d *Decimal) applyPolicy(p Policy) {
+	*d = d.WithPolicy(p)
+}
+
+// Equal reports whether d and e are both null, or both valid and the same
+// number, so 1.5 equals 1.50.
+func (d Decimal) Equal(e Decimal) bool {
+	return d.Compare(e) == 0
+}
+
+// EncodeValues writes the value without trailing zeros after the decimal
+// point, so that equal amounts always give the same query string, e.g. for
+// signed requests.
+func (d Decimal) EncodeValues(key string, v *url.Values) error {
+	return d.encodeValues(key, v, DefaultPolicy)
+}
+
+func (d Decimal) encodeValues(key string, v *url.Values, p Policy) error {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return nil
+	}
+	v.Set(key, d.canonical())
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The value is written as a number, or as a string if DecimalJSONString is
+// set.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return d.marshalJSON(DefaultPolicy)
+}
+
+func (d Decimal) marshalJSON(p Policy) ([]byte, error) {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	if DecimalJSONString {
+		return json.Marshal(d.String())
+	}
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts null, numbers and numeric strings; "" is null.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	return d.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (d *Decimal) unmarshalJSON(data []byte, p Policy) error {
+	v, err := decodeLenientJSON(data, "Decimal")
+	if err != nil {
+		*d = Decimal{}
+		return err
+	}
+	switch s := v.(type) {
+	case nil:
+		*d = Decimal{}
+	case string:
+		return d.unmarshalText([]byte(s), p)
+	case json.Number:
+		return d.unmarshalText([]byte(s), p)
+	default:
+		*d = Decimal{}
+		return &ScanError{Src: s, Type: "Decimal", Err: ErrUnsupportedType}
+	}
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return d.marshalText(DefaultPolicy)
+}
+
+func (d Decimal) marshalText(p Policy) ([]byte, error) {
+	return []byte(d.WithPolicy(p).String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Empty text and "null" are null.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	return d.unmarshalText(text, DefaultPolicy)
+}
+
+func (d *Decimal) unmarshalText(text []byte, p Policy) error {
+	s := p.text(string(text))
+	if s == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	v, err := parseDecimal(strings.TrimSpace(s))
+	if err != nil {
+		*d = Decimal{}
+		return &ScanError{Src: s, Type: "Decimal", Err: err}
+	}
+	*d = v.WithPolicy(p)
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// The value is written as a number with all the digits of its scale.
+func (d Decimal) MarshalYAML() (any, error) {
+	return d.marshalYAML(DefaultPolicy)
+}
+
+func (d Decimal) marshalYAML(p Policy) (any, error) {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return nil, nil
+	}
+	return yamlNumber(d.String()), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The value is parsed the same way as UnmarshalText.
+func (d *Decimal) UnmarshalYAML(value *yaml.Node) error {
+	return d.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (d *Decimal) unmarshalYAML(value *yaml.Node, p Policy) error {
+	s, valid, err := yamlText(value, "nulled.Decimal")
+	if err != nil || !valid {
+		*d = Decimal{}
+		return err
+	}
+	return d.unmarshalText([]byte(s), p)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (d Decimal) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	d = d.WithPolicy(DefaultPolicy)
+	return marshalXMLElement(e, start, d.String(), d.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (d *Decimal) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(dec, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*d = Decimal{}
+		return nil
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (d Decimal) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	d = d.WithPolicy(DefaultPolicy)
+	if !d.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: d.String()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (d *Decimal) UnmarshalXMLAttr(attr xml.Attr) error {
+	return d.UnmarshalText([]byte(attr.Value))
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts the text in string or []byte form that drivers return for
+// NUMERIC and DECIMAL columns, integers, and floats for columns such as
+// SQLite REAL, which are read from their shortest decimal form.
+func (d *Decimal) Scan(value any) error {
+	return d.scan(value, DefaultPolicy)
+}
+
+func (d *Decimal) scan(value any, p Policy) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		*d = Decimal{}
+		return nil
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return &ScanError{Src: value, Type: "Decimal", Err: strconv.ErrSyntax}
+		}
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case float32:
+		s = strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	default:
+		if text, ok := toText(value); ok {
+			s = strings.TrimSpace(text)
+			break
+		}
+		n, _, err := toInt64(value)
+		if err != nil {
+			if scanErr, ok := err.(*ScanError); ok {
+				scanErr.Type = "Decimal"
+			}
+			return err
+		}
+		s = strconv.FormatInt(n, 10)
+	}
+	v, err := parseDecimal(s)
+	if err != nil {
+		return &ScanError{Src: value, Type: "Decimal", Err: err}
+	}
+	*d = v.WithPolicy(p)
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// A valid value is written as text with all the digits of its scale, which
+// NUMERIC and DECIMAL columns store exactly.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.value(DefaultPolicy)
+}
+
+func (d Decimal) value(p Policy) (driver.Value, error) {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return nil, nil
+	}
+	return d.String(), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (d Decimal) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(d.Valid)
+	if !d.Valid {
+		return b, nil
+	}
+	coef, err := d.int().GobEncode()
+	if err != nil {
+		return nil, err
+	}
+	b = binary.AppendUvarint(b, uint64(d.scale))
+	return append(b, coef...), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (d *Decimal) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*d = Decimal{}
+		return nil
+	}
+	scale, size := binary.Uvarint(p)
+	if size <= 0 || scale > math.MaxInt32 {
+		return ErrInvalidBinary
+	}
+	coef := new(big.Int)
+	if err := coef.GobDecode(p[size:]); err != nil {
+		return ErrInvalidBinary
+	}
+	*d = Decimal{coef: coef, scale: int32(scale), Valid: true}
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (d Decimal) GobEncode() ([]byte, error) {
+	return d.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (d *Decimal) GobDecode(data []byte) error {
+	return d.UnmarshalBinary(data)
+}
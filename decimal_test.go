@@ -0,0 +1,319 @@
+package nulled
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"math/big"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func mustDecimal(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	require.NoError(t, err)
+	return d
+}
+
+func TestDecimal_Constructors(t *testing.T) {
+	d := DecimalFrom(1990, 2)
+	assert.True(t, d.Valid)
+	assert.Equal(t, "19.90", d.String())
+	assert.Equal(t, int32(2), d.Scale())
+	assert.Equal(t, big.NewInt(1990), d.Unscaled())
+
+	assert.Equal(t, "1500", DecimalFrom(15, -2).String())
+	assert.Equal(t, "0", NewDecimal(nil, 0, true).String())
+	assert.False(t, NewDecimal(big.NewInt(1), 0, false).Valid)
+	assert.Nil(t, Decimal{}.Unscaled())
+
+	unscaled := big.NewInt(5)
+	d = NewDecimal(unscaled, 1, true)
+	unscaled.SetInt64(6)
+	assert.Equal(t, "0.5", d.String(), "unscaled is copied")
+	d.Unscaled().SetInt64(7)
+	assert.Equal(t, "0.5", d.String(), "Unscaled returns a copy")
+}
+
+func TestDecimal_Parse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		valid bool
+		err   error
+	}{
+		{input: "19.90", want: "19.90", valid: true},
+		{input: " -0.05 ", want: "-0.05", valid: true},
+		{input: "+7", want: "7", valid: true},
+		{input: ".5", want: "0.5", valid: true},
+		{input: "5.", want: "5", valid: true},
+		{input: "1.5e3", want: "1500", valid: true},
+		{input: "15E-4", want: "0.0015", valid: true},
+		{input: "123456789012345678901234567890.123456789", want: "123456789012345678901234567890.123456789", valid: true},
+		{input: "", valid: false},
+		{input: "abc", err: strconv.ErrSyntax},
+		{input: "1.2.3", err: strconv.ErrSyntax},
+		{input: "-", err: strconv.ErrSyntax},
+		{input: "1e", err: strconv.ErrSyntax},
+		{input: "NaN", err: strconv.ErrSyntax},
+		{input: "1e999999999", err: strconv.ErrRange},
+		{input: "1e99999", err: strconv.ErrRange},
+		{input: "1e-99999", err: strconv.ErrRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			d, err := ParseDecimal(tt.input)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.valid, d.Valid)
+			assert.Equal(t, tt.want, d.String())
+		})
+	}
+}
+
+func TestDecimal_Arithmetic(t *testing.T) {
+	a, b := mustDecimal(t, "0.1"), mustDecimal(t, "0.2")
+	assert.Equal(t, "0.3", a.Add(b).String())
+	assert.Equal(t, "19.95", mustDecimal(t, "19.9").Add(mustDecimal(t, "0.05")).String())
+	assert.Equal(t, "-0.1", a.Sub(b).String())
+	assert.Equal(t, "0.02", a.Mul(b).String())
+	assert.Equal(t, "23.8800", mustDecimal(t, "19.90").Mul(mustDecimal(t, "1.20")).String())
+
+	assert.False(t, a.Add(Decimal{}).Valid)
+	assert.False(t, Decimal{}.Sub(a).Valid)
+	assert.False(t, a.Mul(Decimal{}).Valid)
+	assert.Equal(t, "0.1", a.String(), "operands are not modified")
+}
+
+func TestDecimal_Quantize(t *testing.T) {
+	tests := []struct {
+		mode RoundingMode
+		want []string // for 2.5, -2.5, 3.5, 2.4 and 2.6 in order
+	}{
+		{mode: RoundHalfUp, want: []string{"3", "-3", "4", "2", "3"}},
+		{mode: RoundHalfEven, want: []string{"2", "-2", "4", "2", "3"}},
+		{mode: RoundHalfDown, want: []string{"2", "-2", "3", "2", "3"}},
+		{mode: RoundDown, want: []string{"2", "-2", "3", "2", "2"}},
+		{mode: RoundUp, want: []string{"3", "-3", "4", "3", "3"}},
+		{mode: RoundCeiling, want: []string{"3", "-2", "4", "3", "3"}},
+		{mode: RoundFloor, want: []string{"2", "-3", "3", "2", "2"}},
+	}
+
+	inputs := []string{"2.5", "-2.5", "3.5", "2.4", "2.6"}
+	for _, tt := range tests {
+		for i, input := range inputs {
+			got := mustDecimal(t, input).Quantize(0, tt.mode)
+			assert.Equal(t, tt.want[i], got.String(), "mode %d, input %s", tt.mode, input)
+		}
+	}
+
+	assert.Equal(t, "19.90", mustDecimal(t, "19.9").Quantize(2, RoundHalfUp).String())
+	assert.Equal(t, "23.88", mustDecimal(t, "23.8810").Quantize(2, RoundHalfEven).String())
+	assert.Equal(t, "0.13", mustDecimal(t, "0.125").Quantize(2, RoundHalfUp).String())
+	assert.Equal(t, "0.12", mustDecimal(t, "0.125").Quantize(2, RoundHalfEven).String())
+	assert.Equal(t, "-0.01", mustDecimal(t, "-0.001").Quantize(2, RoundFloor).String())
+	assert.Equal(t, "0.00", mustDecimal(t, "-0.001").Quantize(2, RoundCeiling).String())
+	assert.Equal(t, "2", mustDecimal(t, "1.5").Quantize(-1, RoundHalfUp).String())
+	assert.False(t, Decimal{}.Quantize(2, RoundHalfUp).Valid)
+}
+
+func TestDecimal_Compare(t *testing.T) {
+	assert.Equal(t, 0, mustDecimal(t, "1.5").Compare(mustDecimal(t, "1.50")))
+	assert.Equal(t, -1, mustDecimal(t, "1.49").Compare(mustDecimal(t, "1.5")))
+	assert.Equal(t, 1, mustDecimal(t, "-1").Compare(mustDecimal(t, "-1.01")))
+	assert.Equal(t, -1, Decimal{}.Compare(mustDecimal(t, "-100")))
+	assert.Equal(t, 1, mustDecimal(t, "0").Compare(Decimal{}))
+
+	assert.True(t, mustDecimal(t, "1.5").Equal(mustDecimal(t, "1.500")))
+	assert.True(t, Decimal{}.Equal(Decimal{}))
+	assert.False(t, mustDecimal(t, "0").Equal(Decimal{}))
+
+	assert.Equal(t, -1, mustDecimal(t, "-0.5").Sign())
+	assert.Equal(t, 0, mustDecimal(t, "0.00").Sign())
+}
+
+func TestDecimal_JSON(t *testing.T) {
+	type invoice struct {
+		Total Decimal `json:"total"`
+		Tax   Decimal `json:"tax"`
+	}
+
+	var in invoice
+	require.NoError(t, json.Unmarshal([]byte(`{"total": 19.90, "tax": "0.10000000000000000001"}`), &in))
+	assert.Equal(t, "19.90", in.Total.String())
+	assert.Equal(t, "0.10000000000000000001", in.Tax.String())
+
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"total":19.90,"tax":0.10000000000000000001}`, string(data))
+
+	DecimalJSONString = true
+	t.Cleanup(func() { DecimalJSONString = false })
+	data, err = json.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, `{"total":"19.90","tax":"0.10000000000000000001"}`, string(data))
+
+	data, err = json.Marshal(invoice{})
+	require.NoError(t, err)
+	assert.Equal(t, `{"total":null,"tax":null}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"total": null, "tax": ""}`), &in))
+	assert.False(t, in.Total.Valid)
+	assert.False(t, in.Tax.Valid)
+
+	var d Decimal
+	assert.ErrorIs(t, d.UnmarshalJSON([]byte(`"abc"`)), strconv.ErrSyntax)
+	assert.ErrorIs(t, d.UnmarshalJSON([]byte(`true`)), ErrUnsupportedType)
+	assert.Error(t, d.UnmarshalJSON([]byte(`{}`)))
+}
+
+func TestDecimal_Text(t *testing.T) {
+	text, err := DecimalFrom(-5, 2).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "-0.05", string(text))
+
+	text, err = Decimal{}.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	var d Decimal
+	require.NoError(t, d.UnmarshalText([]byte(" 1.10 ")))
+	assert.Equal(t, "1.10", d.String())
+	require.NoError(t, d.UnmarshalText([]byte("null")))
+	assert.False(t, d.Valid)
+	assert.ErrorIs(t, d.UnmarshalText([]byte("1,10")), strconv.ErrSyntax)
+}
+
+func TestDecimal_YAML(t *testing.T) {
+	type order struct {
+		Price Decimal `yaml:"price"`
+		Tax   Decimal `yaml:"tax"`
+		Total Decimal `yaml:"total"`
+	}
+	n, ok := new(big.Int).SetString("123456789012345678901234567890", 10)
+	require.True(t, ok)
+	in := order{Price: DecimalFrom(150, 2), Total: NewDecimal(n, 2, true)}
+	data, err := yaml.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, "price: 1.50\ntax: null\ntotal: 1234567890123456789012345678.90\n", string(data))
+
+	var out order
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+	require.NoError(t, yaml.Unmarshal([]byte("price: \"19.90\"\ntax: 1e2\n"), &out))
+	assert.Equal(t, DecimalFrom(1990, 2), out.Price)
+	assert.Equal(t, 0, out.Tax.Compare(DecimalFrom(100, 0)))
+	assert.Error(t, yaml.Unmarshal([]byte("price: abc"), &out))
+	assert.Error(t, yaml.Unmarshal([]byte("price: [1]"), &out))
+}
+
+func TestDecimal_XML(t *testing.T) {
+	type order struct {
+		XMLName xml.Name `xml:"order"`
+		Price   Decimal  `xml:"price,attr"`
+		Tax     Decimal  `xml:"tax"`
+		Total   Decimal  `xml:"total"`
+	}
+	data, err := xml.Marshal(order{Price: DecimalFrom(150, 2), Total: DecimalFrom(1990, 2)})
+	require.NoError(t, err)
+	assert.Equal(t, `<order price="1.50"><tax xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></tax><total>19.90</total></order>`, string(data))
+
+	var out order
+	require.NoError(t, xml.Unmarshal(data, &out))
+	assert.Equal(t, order{XMLName: xml.Name{Local: "order"}, Price: DecimalFrom(150, 2), Total: DecimalFrom(1990, 2)}, out)
+	assert.Error(t, xml.Unmarshal([]byte(`<order><tax>x</tax></order>`), &out))
+}
+
+func TestDecimal_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, mustDecimal(t, "19.90").EncodeValues("price", &v))
+	require.NoError(t, mustDecimal(t, "100").EncodeValues("qty", &v))
+	require.NoError(t, mustDecimal(t, "0.000").EncodeValues("discount", &v))
+	require.NoError(t, mustDecimal(t, "1e-7").EncodeValues("rate", &v))
+	require.NoError(t, Decimal{}.EncodeValues("skip", &v))
+	assert.Equal(t, url.Values{"price": {"19.9"}, "qty": {"100"}, "discount": {"0"}, "rate": {"0.0000001"}}, v)
+}
+
+func TestDecimal_Scan(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   any
+		want  string
+		valid bool
+	}{
+		{name: "numeric bytes", src: []byte("19.90"), want: "19.90", valid: true},
+		{name: "numeric string", src: "-123456789012345678901234.5", want: "-123456789012345678901234.5", valid: true},
+		{name: "int64", src: int64(42), want: "42", valid: true},
+		{name: "uint64", src: uint64(18446744073709551615), want: "18446744073709551615", valid: true},
+		{name: "float64", src: 0.1, want: "0.1", valid: true},
+		{name: "float32", src: float32(1.1), want: "1.1", valid: true},
+		{name: "nil", src: nil, valid: false},
+		{name: "blank", src: " ", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := DecimalFrom(1, 0)
+			require.NoError(t, d.Scan(tt.src))
+			assert.Equal(t, tt.valid, d.Valid)
+			assert.Equal(t, tt.want, d.String())
+		})
+	}
+
+	d := DecimalFrom(1, 0)
+	assert.ErrorIs(t, d.Scan("abc"), strconv.ErrSyntax)
+	assert.ErrorIs(t, d.Scan(struct{}{}), ErrUnsupportedType)
+	assert.Equal(t, "1", d.String(), "unchanged on error")
+}
+
+func TestDecimal_Value(t *testing.T) {
+	v, err := mustDecimal(t, "19.90").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "19.90", v)
+
+	v, err = Decimal{}.Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestDecimal_SQL(t *testing.T) {
+	db := openTestDB(t)
+
+	_, err := db.Exec("INSERT", mustDecimal(t, "-0.0100"), Decimal{})
+	require.NoError(t, err)
+
+	var a, b Decimal
+	b = DecimalFrom(1, 0)
+	require.NoError(t, db.QueryRow("SELECT").Scan(&a, &b))
+	assert.Equal(t, "-0.0100", a.String())
+	assert.False(t, b.Valid)
+}
+
+func TestDecimal_Binary(t *testing.T) {
+	for _, s := range []string{"19.90", "-0.05", "0", "123456789012345678901234567890.5"} {
+		d := mustDecimal(t, s)
+		data, err := d.MarshalBinary()
+		require.NoError(t, err)
+
+		var got Decimal
+		require.NoError(t, got.UnmarshalBinary(data))
+		assert.Equal(t, s, got.String())
+	}
+
+	data, err := Decimal{}.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0}, data)
+
+	var d Decimal
+	assert.ErrorIs(t, d.UnmarshalBinary([]byte{1}), ErrInvalidBinary)
+	assert.ErrorIs(t, d.UnmarshalBinary([]byte{1, 2, 0xff}), ErrInvalidBinary)
+}
@@ -0,0 +1,625 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a nullable time.Duration.
+type Duration struct {
+	Duration time.Duration
+	Valid    bool
+}
+
+// DurationFormat selects how a Duration is encoded as JSON and text.
+type DurationFormat int
+
+const (
+	// DurationGo writes Go duration strings such as "1h30m0s".
+	DurationGo DurationFormat = iota
+	// DurationISO8601 writes ISO 8601 durations such as "PT1H30M".
+	DurationISO8601
+	// DurationNumber writes numbers in DurationUnit, e.g. 5400000 for
+	// milliseconds. JSON numbers are written without quotes.
+	DurationNumber
+)
+
+var (
+	// DurationUnit is the unit of durations given as numbers, in JSON or as
+	// numeric text, and of numbers written with DurationNumber. Milliseconds
+	// are used if it is not positive.
+	DurationUnit = time.Millisecond
+
+	// DurationOutput is the form Duration is encoded in.
+	DurationOutput = DurationGo
+
+	// DurationValueInterval makes Value return PostgreSQL interval text such
+	// as "01:30:00" instead of an int64 count of nanoseconds for BIGINT
+	// columns. Scan accepts both whatever the setting.
+	DurationValueInterval = false
+)
+
+func NewDuration(d time.Duration, valid bool) Duration {
+	return Duration{Duration: d, Valid: valid}
+}
+
+// DurationFrom creates a Duration from d under DefaultPolicy.
+func DurationFrom(d time.Duration) Duration {
+	return NewDuration(d, true).WithPolicy(DefaultPolicy)
+}
+
+func DurationFromPtr(d *time.Duration) Duration {
+	if d == nil {
+		return NewDuration(0, false)
+	}
+	return DurationFrom(*d)
+}
+
+func (d Duration) ValueOrZero() time.Duration {
+	if !d.Valid {
+		return 0
+	}
+	return d.Duration
+}
+
+// IsZero reports whether the value is null.
+func (d Duration) IsZero() bool {
+	return !d.Valid
+}
+
+// WithPolicy returns the value nulled according to p.
+func (d Duration) WithPolicy(p Policy) Duration {
+	if !d.Valid || (p.ZeroAsNull && d.Duration == 0) {
+		return NewDuration(0, false)
+	}
+	return d
+}
+
+func (d *Duration) applyPolicy(p Policy) {
+	*d = d.WithPolicy(p)
+}
+
+// durationUnit returns DurationUnit, or a millisecond if it is not positive.
+func durationUnit() time.Duration {
+	if DurationUnit <= 0 {
+		return time.Millisecond
+	}
+	return DurationUnit
+}
+
+// parseDurationNumber parses s as a number of DurationUnit.
+func parseDurationNumber(s string) (time.Duration, error) {
+	unit := durationUnit()
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n > math.MaxInt64/int64(unit) || n < math.MinInt64/int64(unit) {
+			return 0, strconv.ErrRange
+		}
+		return time.Duration(n) * unit, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, strconv.ErrSyntax
+	}
+	f = math.Round(f * float64(unit))
+	// float64(math.MaxInt64) rounds up to 2^63, which is already out of range
+	if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, strconv.ErrRange
+	}
+	return time.Duration(f), nil
+}
+
+// mulDuration returns d*n, or strconv.ErrRange if it overflows.
+func mulDuration(d time.Duration, n int64) (time.Duration, error) {
+	if d == 0 || n == 0 {
+		return 0, nil
+	}
+	p := d * time.Duration(n)
+	if p/time.Duration(n) != d || (d == -1 && n == math.MinInt64) || (n == -1 && d == math.MinInt64) {
+		return 0, strconv.ErrRange
+	}
+	return p, nil
+}
+
+// addDuration returns a+b, or strconv.ErrRange if it overflows.
+func addDuration(a, b time.Duration) (time.Duration, error) {
+	sum := a + b
+	if (b > 0 && sum < a) || (b < 0 && sum > a) {
+		return 0, strconv.ErrRange
+	}
+	return sum, nil
+}
+
+// parseDuration parses a Go duration string, an ISO 8601 duration, a
+// PostgreSQL interval or a number of DurationUnit.
+func parseDuration(s string) (time.Duration, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	for _, parse := range []func(string) (time.Duration, error){parseISO8601Duration, parseInterval} {
+		if d, err := parse(s); err == nil || errors.Is(err, strconv.ErrRange) {
+			return d, err
+		}
+	}
+	return parseDurationNumber(s)
+}
+
+var errDurationSyntax = errors.New("nulled: invalid duration")
+
+// parseISO8601Duration parses durations such as "PT1H30M", "P1DT12H",
+// "P2W" and "-PT0.5S". Years and months are rejected since their length
+// varies; a day is 24 hours. Durations beyond the time.Duration range are
+// reported as strconv.ErrRange.
+func parseISO8601Duration(s string) (time.Duration, error) {
+	neg := false
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		neg, s = true, rest
+	}
+	s, ok := strings.CutPrefix(strings.ToUpper(s), "P")
+	if !ok || s == "" {
+		return 0, errDurationSyntax
+	}
+
+	var d time.Duration
+	inTime, timeParts := false, 0
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime {
+				return 0, errDurationSyntax
+			}
+			inTime, s = true, s[1:]
+			continue
+		}
+		i := strings.IndexAny(s, "WDHMS")
+		if i <= 0 {
+			return 0, errDurationSyntax
+		}
+		num, designator := strings.Replace(s[:i], ",", ".", 1), s[i]
+		s = s[i+1:]
+
+		var unit string
+		scale := int64(1)
+		switch {
+		case designator == 'W' && !inTime:
+			unit, scale = "h", 7*24
+		case designator == 'D' && !inTime:
+			unit, scale = "h", 24
+		case designator == 'H' && inTime:
+			unit = "h"
+		case designator == 'M' && inTime:
+			unit = "m"
+		case designator == 'S' && inTime:
+			unit = "s"
+		default:
+			return 0, errDurationSyntax
+		}
+		if inTime {
+			timeParts++
+		}
+		if num[0] == '-' || num[0] == '+' {
+			return 0, errDurationSyntax
+		}
+		v, err := time.ParseDuration(num + unit)
+		if err != nil {
+			// well-formed numbers only fail when they overflow
+			if digits := strings.Replace(num, ".", "", 1); digits != "" && strings.Trim(digits, "0123456789") == "" {
+				return 0, strconv.ErrRange
+			}
+			return 0, errDurationSyntax
+		}
+		if v, err = mulDuration(v, scale); err != nil {
+			return 0, err
+		}
+		if d, err = addDuration(d, v); err != nil {
+			return 0, err
+		}
+	}
+	// "T" must be followed by hours, minutes or seconds
+	if inTime && timeParts == 0 {
+		return 0, errDurationSyntax
+	}
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// formatISO8601Duration formats d as an ISO 8601 duration in hours, minutes
+// and seconds, e.g. "PT36H" or "-PT1.5S".
+func formatISO8601Duration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var b strings.Builder
+	u := uint64(d)
+	if d < 0 {
+		b.WriteByte('-')
+		u = -u
+	}
+	b.WriteString("PT")
+	h, u := u/uint64(time.Hour), u%uint64(time.Hour)
+	m, u := u/uint64(time.Minute), u%uint64(time.Minute)
+	if h > 0 {
+		b.WriteString(strconv.FormatUint(h, 10) + "H")
+	}
+	if m > 0 {
+		b.WriteString(strconv.FormatUint(m, 10) + "M")
+	}
+	if u > 0 {
+		sec := strconv.FormatUint(u/uint64(time.Second), 10)
+		if frac := u % uint64(time.Second); frac > 0 {
+			sec += strings.TrimRight(fmt.Sprintf(".%09d", frac), "0")
+		}
+		b.WriteString(sec + "S")
+	}
+	return b.String()
+}
+
+// parseInterval parses PostgreSQL interval output in the default postgres
+// style, such as "01:30:00", "-00:00:00.5", "3 days" or "1 day 02:00:00".
+// Years and months are rejected since their length varies. Intervals beyond
+// the time.Duration range are reported as strconv.ErrRange.
+func parseInterval(s string) (time.Duration, error) {
+	var d time.Duration
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, errDurationSyntax
+	}
+	if len(fields) >= 2 && (fields[1] == "day" || fields[1] == "days") {
+		n, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			if errors.Is(err, strconv.ErrRange) {
+				return 0, strconv.ErrRange
+			}
+			return 0, errDurationSyntax
+		}
+		if d, err = mulDuration(24*time.Hour, n); err != nil {
+			return 0, err
+		}
+		fields = fields[2:]
+	}
+	switch len(fields) {
+	case 0:
+		return d, nil
+	case 1:
+	default:
+		return 0, errDurationSyntax
+	}
+
+	clock := fields[0]
+	neg := false
+	if rest, ok := strings.CutPrefix(clock, "-"); ok {
+		neg, clock = true, rest
+	} else {
+		clock = strings.TrimPrefix(clock, "+")
+	}
+	parts := strings.Split(clock, ":")
+	if len(parts) != 3 {
+		return 0, errDurationSyntax
+	}
+	h, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, errDurationSyntax
+	}
+	m, err := strconv.ParseUint(parts[1], 10, 8)
+	if err != nil || m > 59 {
+		return 0, errDurationSyntax
+	}
+	sec, err := time.ParseDuration(parts[2] + "s")
+	if err != nil || sec < 0 || sec >= time.Minute {
+		return 0, errDurationSyntax
+	}
+	c, err := mulDuration(time.Hour, int64(h))
+	if err != nil {
+		return 0, err
+	}
+	if c, err = addDuration(c, time.Duration(m)*time.Minute+sec); err != nil {
+		return 0, err
+	}
+	if neg {
+		c = -c
+	}
+	return addDuration(d, c)
+}
+
+// formatInterval formats d as PostgreSQL interval text, e.g. "36:00:00" or
+// "-00:00:01.5".
+func formatInterval(d time.Duration) string {
+	sign := ""
+	u := uint64(d)
+	if d < 0 {
+		sign = "-"
+		u = -u
+	}
+	h, u := u/uint64(time.Hour), u%uint64(time.Hour)
+	m, u := u/uint64(time.Minute), u%uint64(time.Minute)
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign, h, m, u/uint64(time.Second))
+	if frac := u % uint64(time.Second); frac > 0 {
+		s += strings.TrimRight(fmt.Sprintf(".%09d", frac), "0")
+	}
+	return s
+}
+
+// text returns the duration in the DurationOutput form.
+func (d Duration) text() string {
+	switch DurationOutput {
+	case DurationISO8601:
+		return formatISO8601Duration(d.Duration)
+	case DurationNumber:
+		unit := durationUnit()
+		if d.Duration%unit == 0 {
+			return strconv.FormatInt(int64(d.Duration/unit), 10)
+		}
+		return strconv.FormatFloat(float64(d.Duration)/float64(unit), 'f', -1, 64)
+	}
+	return d.Duration.String()
+}
+
+func (d Duration) EncodeValues(key string, v *url.Values) error {
+	return d.encodeValues(key, v, DefaultPolicy)
+}
+
+func (d Duration) encodeValues(key string, v *url.Values, p Policy) error {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return nil
+	}
+	v.Set(key, d.text())
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The duration is encoded in the DurationOutput form.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return d.marshalJSON(DefaultPolicy)
+}
+
+func (d Duration) marshalJSON(p Policy) ([]byte, error) {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	if DurationOutput == DurationNumber {
+		return []byte(d.text()), nil
+	}
+	return json.Marshal(d.text())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Numbers are read in DurationUnit, strings like UnmarshalText.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	return d.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (d *Duration) unmarshalJSON(data []byte, p Policy) error {
+	v, err := decodeLenientJSON(data, "Duration")
+	if err != nil {
+		*d = NewDuration(0, false)
+		return err
+	}
+	switch s := v.(type) {
+	case nil:
+		*d = NewDuration(0, false)
+	case string:
+		return d.unmarshalText([]byte(s), p)
+	case json.Number:
+		n, err := parseDurationNumber(string(s))
+		if err != nil {
+			*d = NewDuration(0, false)
+			return &ScanError{Src: string(s), Type: "Duration", Err: err}
+		}
+		*d = NewDuration(n, true).WithPolicy(p)
+	default:
+		*d = NewDuration(0, false)
+		return &ScanError{Src: s, Type: "Duration", Err: ErrUnsupportedType}
+	}
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// The duration is encoded in the DurationOutput form, a null value as empty
+// text.
+func (d Duration) MarshalText() ([]byte, error) {
+	return d.marshalText(DefaultPolicy)
+}
+
+func (d Duration) marshalText(p Policy) ([]byte, error) {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return []byte{}, nil
+	}
+	return []byte(d.text()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// It accepts Go duration strings, ISO 8601 durations, PostgreSQL intervals
+// and numbers in DurationUnit. Empty text and "null" are null.
+func (d *Duration) UnmarshalText(text []byte) error {
+	return d.unmarshalText(text, DefaultPolicy)
+}
+
+func (d *Duration) unmarshalText(text []byte, p Policy) error {
+	s := p.text(string(text))
+	if strings.TrimSpace(s) == "" || s == "null" {
+		*d = NewDuration(0, false)
+		return nil
+	}
+	v, err := parseDuration(strings.TrimSpace(s))
+	if err != nil {
+		*d = NewDuration(0, false)
+		if !errors.Is(err, strconv.ErrRange) {
+			err = strconv.ErrSyntax
+		}
+		return &ScanError{Src: s, Type: "Duration", Err: err}
+	}
+	*d = NewDuration(v, true).WithPolicy(p)
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// The duration is encoded in the DurationOutput form.
+func (d Duration) MarshalYAML() (any, error) {
+	return d.marshalYAML(DefaultPolicy)
+}
+
+func (d Duration) marshalYAML(p Policy) (any, error) {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return nil, nil
+	}
+	return yamlNumber(d.text()), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The value is parsed the same way as UnmarshalText.
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	return d.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (d *Duration) unmarshalYAML(value *yaml.Node, p Policy) error {
+	s, valid, err := yamlText(value, "nulled.Duration")
+	if err != nil || !valid {
+		*d = NewDuration(0, false)
+		return err
+	}
+	return d.unmarshalText([]byte(s), p)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (d Duration) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	d = d.WithPolicy(DefaultPolicy)
+	return marshalXMLElement(e, start, d.text(), d.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (d *Duration) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(dec, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*d = NewDuration(0, false)
+		return nil
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (d Duration) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	d = d.WithPolicy(DefaultPolicy)
+	if !d.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: d.text()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (d *Duration) UnmarshalXMLAttr(attr xml.Attr) error {
+	return d.UnmarshalText([]byte(attr.Value))
+}
+
+// Scan implements the sql.Scanner interface.
+// Integers, including integer text, are nanoseconds as written to BIGINT
+// columns. Other text is parsed like UnmarshalText, which covers PostgreSQL
+// interval output.
+func (d *Duration) Scan(value any) error {
+	return d.scan(value, DefaultPolicy)
+}
+
+func (d *Duration) scan(value any, p Policy) error {
+	if value == nil {
+		*d = NewDuration(0, false)
+		return nil
+	}
+	if s, ok := toText(value); ok {
+		s = strings.TrimSpace(s)
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil && s != "" {
+			v, err := parseDuration(s)
+			if err != nil {
+				if !errors.Is(err, strconv.ErrRange) {
+					err = strconv.ErrSyntax
+				}
+				return &ScanError{Src: value, Type: "Duration", Err: err}
+			}
+			*d = NewDuration(v, true).WithPolicy(p)
+			return nil
+		}
+	}
+	n, valid, err := toInt64(value)
+	if err != nil {
+		var scanErr *ScanError
+		if errors.As(err, &scanErr) {
+			scanErr.Src, scanErr.Type = value, "Duration"
+		}
+		return err
+	}
+	*d = NewDuration(time.Duration(n), valid).WithPolicy(p)
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// A valid duration is written as an int64 count of nanoseconds, or as
+// PostgreSQL interval text if DurationValueInterval is set.
+func (d Duration) Value() (driver.Value, error) {
+	return d.value(DefaultPolicy)
+}
+
+func (d Duration) value(p Policy) (driver.Value, error) {
+	d = d.WithPolicy(p)
+	if !d.Valid {
+		return nil, nil
+	}
+	if DurationValueInterval {
+		return formatInterval(d.Duration), nil
+	}
+	return int64(d.Duration), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (d Duration) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(d.Valid)
+	if !d.Valid {
+		return b, nil
+	}
+	return binary.AppendVarint(b, int64(d.Duration)), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (d *Duration) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*d = NewDuration(0, false)
+		return nil
+	}
+	n, size := binary.Varint(p)
+	if size <= 0 || size != len(p) {
+		return ErrInvalidBinary
+	}
+	*d = NewDuration(time.Duration(n), true)
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (d Duration) GobEncode() ([]byte, error) {
+	return d.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (d *Duration) GobDecode(data []byte) error {
+	return d.UnmarshalBinary(data)
+}
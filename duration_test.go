@@ -0,0 +1,266 @@
+package nulled
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testDuration = time.Hour + 30*time.Minute
+
+// withDurationOptions sets the Duration options for the rest of the test.
+func withDurationOptions(t *testing.T, unit time.Duration, output DurationFormat, interval bool) {
+	oldUnit, oldOutput, oldInterval := DurationUnit, DurationOutput, DurationValueInterval
+	DurationUnit, DurationOutput, DurationValueInterval = unit, output, interval
+	t.Cleanup(func() {
+		DurationUnit, DurationOutput, DurationValueInterval = oldUnit, oldOutput, oldInterval
+	})
+}
+
+func TestDuration_New(t *testing.T) {
+	d := testDuration
+	assert.Equal(t, Duration{Duration: testDuration, Valid: true}, DurationFrom(testDuration))
+	assert.Equal(t, DurationFrom(testDuration), DurationFromPtr(&d))
+	assert.Equal(t, NewDuration(0, false), DurationFromPtr(nil))
+	assert.Equal(t, time.Duration(0), NewDuration(testDuration, false).ValueOrZero())
+	assert.True(t, Duration{}.IsZero())
+	assert.False(t, DurationFrom(0).IsZero())
+}
+
+func TestDuration_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Duration
+		wantErr error
+	}{
+		{text: "1h30m", want: DurationFrom(testDuration)},
+		{text: "PT1H30M", want: DurationFrom(testDuration)},
+		{text: "pt90m", want: DurationFrom(testDuration)},
+		{text: "P1DT12H", want: DurationFrom(36 * time.Hour)},
+		{text: "P2W", want: DurationFrom(14 * 24 * time.Hour)},
+		{text: "-PT0,5S", want: DurationFrom(-500 * time.Millisecond)},
+		{text: "PT1.5H", want: DurationFrom(testDuration)},
+		{text: "01:30:00", want: DurationFrom(testDuration)},
+		{text: "-00:00:00.25", want: DurationFrom(-250 * time.Millisecond)},
+		{text: "1 day 12:00:00", want: DurationFrom(36 * time.Hour)},
+		{text: "3 days", want: DurationFrom(72 * time.Hour)},
+		{text: "5400000", want: DurationFrom(testDuration)},
+		{text: "0.5", want: DurationFrom(500 * time.Microsecond)},
+		{text: " ", want: NewDuration(0, false)},
+		{text: "null", want: NewDuration(0, false)},
+		{text: "P1Y", wantErr: strconv.ErrSyntax},
+		{text: "1 mon", wantErr: strconv.ErrSyntax},
+		{text: "PT", wantErr: strconv.ErrSyntax},
+		{text: "P1DT", wantErr: strconv.ErrSyntax},
+		{text: "P1H", wantErr: strconv.ErrSyntax},
+		{text: "soon", wantErr: strconv.ErrSyntax},
+		{text: "99999999999999999", wantErr: strconv.ErrRange},
+		{text: "P106751D", want: DurationFrom(106751 * 24 * time.Hour)},
+		{text: "P200000D", wantErr: strconv.ErrRange},
+		{text: "P20000W", wantErr: strconv.ErrRange},
+		{text: "PT3000000H", wantErr: strconv.ErrRange},
+		{text: "P106751DT24H", wantErr: strconv.ErrRange},
+		{text: "106751 days 23:47:16.854775807", want: DurationFrom(math.MaxInt64)},
+		{text: "106751 days 23:47:16.854775808", wantErr: strconv.ErrRange},
+		{text: "-200000 days", wantErr: strconv.ErrRange},
+		{text: "4000000000:00:00", wantErr: strconv.ErrRange},
+		{text: "99999999999999999999 days", wantErr: strconv.ErrRange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var d Duration
+			err := d.UnmarshalText([]byte(tt.text))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func TestDuration_NonPositiveUnit(t *testing.T) {
+	for _, unit := range []time.Duration{0, -time.Second} {
+		withDurationOptions(t, unit, DurationNumber, false)
+		var d Duration
+		require.NoError(t, d.UnmarshalText([]byte("1500")))
+		assert.Equal(t, DurationFrom(1500*time.Millisecond), d, "milliseconds are used")
+		text, err := d.MarshalText()
+		require.NoError(t, err)
+		assert.Equal(t, "1500", string(text))
+		require.NoError(t, d.Scan("2.5"))
+		assert.Equal(t, DurationFrom(2500*time.Microsecond), d)
+	}
+}
+
+func TestDuration_JSON(t *testing.T) {
+	type config struct {
+		Timeout Duration `json:"timeout"`
+		SLA     Duration `json:"sla"`
+	}
+	in := config{Timeout: DurationFrom(testDuration)}
+
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"timeout":"1h30m0s","sla":null}`, string(data))
+	var out config
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"timeout":5400000,"sla":"PT1M"}`), &out))
+	assert.Equal(t, config{Timeout: DurationFrom(testDuration), SLA: DurationFrom(time.Minute)}, out)
+	require.NoError(t, json.Unmarshal([]byte(`{"timeout":"","sla":1.5}`), &out))
+	assert.Equal(t, config{SLA: DurationFrom(1500 * time.Microsecond)}, out)
+
+	var scanErr *ScanError
+	assert.True(t, errors.As(json.Unmarshal([]byte(`{"timeout":true}`), &out), &scanErr))
+	assert.True(t, errors.As(json.Unmarshal([]byte(`{"timeout":"soon"}`), &out), &scanErr))
+}
+
+func TestDuration_Output(t *testing.T) {
+	tests := []struct {
+		format DurationFormat
+		unit   time.Duration
+		value  time.Duration
+		json   string
+		text   string
+	}{
+		{format: DurationGo, unit: time.Millisecond, value: testDuration, json: `"1h30m0s"`, text: "1h30m0s"},
+		{format: DurationISO8601, unit: time.Millisecond, value: testDuration, json: `"PT1H30M"`, text: "PT1H30M"},
+		{format: DurationISO8601, unit: time.Millisecond, value: -36*time.Hour - 1500*time.Millisecond, json: `"-PT36H1.5S"`, text: "-PT36H1.5S"},
+		{format: DurationISO8601, unit: time.Millisecond, value: 0, json: `"PT0S"`, text: "PT0S"},
+		{format: DurationNumber, unit: time.Millisecond, value: testDuration, json: `5400000`, text: "5400000"},
+		{format: DurationNumber, unit: time.Second, value: 1500 * time.Millisecond, json: `1.5`, text: "1.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			withDurationOptions(t, tt.unit, tt.format, false)
+			d := DurationFrom(tt.value)
+
+			data, err := json.Marshal(d)
+			require.NoError(t, err)
+			assert.Equal(t, tt.json, string(data))
+			var fromJSON Duration
+			require.NoError(t, json.Unmarshal(data, &fromJSON))
+			assert.Equal(t, d, fromJSON)
+
+			text, err := d.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, string(text))
+			v := url.Values{}
+			require.NoError(t, d.EncodeValues("d", &v))
+			assert.Equal(t, tt.text, v.Get("d"))
+		})
+	}
+
+	text, err := NewDuration(0, false).MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+}
+
+func TestDuration_YAML(t *testing.T) {
+	type job struct {
+		Timeout Duration `yaml:"timeout"`
+		Retry   Duration `yaml:"retry"`
+	}
+	in := job{Timeout: DurationFrom(testDuration)}
+	data, err := yaml.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, "timeout: 1h30m0s\nretry: null\n", string(data))
+
+	var out job
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+	require.NoError(t, yaml.Unmarshal([]byte("timeout: PT2H\n"), &out))
+	assert.Equal(t, DurationFrom(2*time.Hour), out.Timeout)
+	require.NoError(t, yaml.Unmarshal([]byte("timeout: 1500\n"), &out))
+	assert.Equal(t, DurationFrom(1500*time.Millisecond), out.Timeout)
+	assert.Error(t, yaml.Unmarshal([]byte("timeout: soon"), &out))
+	assert.Error(t, yaml.Unmarshal([]byte("timeout: [1]"), &out))
+
+	withDurationOptions(t, time.Second, DurationNumber, false)
+	data, err = yaml.Marshal(job{Timeout: DurationFrom(testDuration)})
+	require.NoError(t, err)
+	assert.Equal(t, "timeout: 5400\nretry: null\n", string(data))
+}
+
+func TestDuration_XML(t *testing.T) {
+	type job struct {
+		XMLName xml.Name `xml:"job"`
+		Timeout Duration `xml:"timeout,attr"`
+		Retry   Duration `xml:"retry"`
+	}
+	data, err := xml.Marshal(job{Timeout: DurationFrom(testDuration)})
+	require.NoError(t, err)
+	assert.Equal(t, `<job timeout="1h30m0s"><retry xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></retry></job>`, string(data))
+
+	var out job
+	require.NoError(t, xml.Unmarshal([]byte(`<job timeout="PT1H30M"><retry>30s</retry></job>`), &out))
+	assert.Equal(t, DurationFrom(testDuration), out.Timeout)
+	assert.Equal(t, DurationFrom(30*time.Second), out.Retry)
+	assert.Error(t, xml.Unmarshal([]byte(`<job timeout="soon"></job>`), &out))
+}
+
+func TestDuration_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Duration
+		wantErr error
+	}{
+		{name: "nil", src: nil, want: NewDuration(0, false)},
+		{name: "bigint", src: int64(testDuration), want: DurationFrom(testDuration)},
+		{name: "bigint text", src: []byte("5400000000000"), want: DurationFrom(testDuration)},
+		{name: "interval", src: []byte("1 day 01:30:00"), want: DurationFrom(24*time.Hour + testDuration)},
+		{name: "blank", src: "", want: NewDuration(0, false)},
+		{name: "invalid", src: "soon", wantErr: strconv.ErrSyntax},
+		{name: "interval overflow", src: "200000 days", wantErr: strconv.ErrRange},
+		{name: "fraction", src: 1.5, wantErr: strconv.ErrSyntax},
+		{name: "unsupported", src: struct{}{}, wantErr: ErrUnsupportedType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Duration
+			err := d.Scan(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var scanErr *ScanError
+				require.ErrorAs(t, err, &scanErr)
+				assert.Equal(t, "Duration", scanErr.Type)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, d)
+		})
+	}
+}
+
+func TestDuration_SQL(t *testing.T) {
+	db := openTestDB(t)
+	for _, interval := range []bool{false, true} {
+		withDurationOptions(t, DurationUnit, DurationOutput, interval)
+		in := DurationFrom(-(36*time.Hour + 1500*time.Millisecond))
+		_, err := db.Exec("INSERT", in, NewDuration(0, false))
+		require.NoError(t, err)
+
+		var a, b Duration
+		require.NoError(t, db.QueryRow("SELECT").Scan(&a, &b))
+		assert.Equal(t, in, a)
+		assert.Equal(t, NewDuration(0, false), b)
+	}
+
+	withDurationOptions(t, DurationUnit, DurationOutput, true)
+	v, err := DurationFrom(36 * time.Hour).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "36:00:00", v)
+}
@@ -1,13 +1,17 @@
 package nulled
 
 import (
-	"bytes"
-	"encoding/gob"
+	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
+	"encoding/xml"
+	"math"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
 )
 
 type Float null.Float
@@ -16,15 +20,16 @@ func NewFloat(i float64, valid bool) Float {
 	return Float(null.NewFloat(i, valid))
 }
 
+// FloatFrom creates a Float from i under DefaultPolicy.
 func FloatFrom(i float64) Float {
-	return NewFloat(i, true)
+	return NewFloat(i, true).WithPolicy(DefaultPolicy)
 }
 
 func FloatFromPtr(f *float64) Float {
 	if f == nil {
 		return NewFloat(0, false)
 	}
-	return NewFloat(*f, true)
+	return FloatFrom(*f)
 }
 
 func (f Float) ValueOrZero() float64 {
@@ -34,72 +39,207 @@ func (f Float) ValueOrZero() float64 {
 	return f.Float64
 }
 
+// IsZero reports whether the value is null.
+func (f Float) IsZero() bool {
+	return !f.Valid
+}
+
+// asValue returns f as the Value it is built on.
+func (f Float) asValue() Value[float64] {
+	return NewValue(f.Float64, f.Valid)
+}
+
+func floatFromValue(v Value[float64]) Float {
+	return NewFloat(v.V, v.Valid)
+}
+
+// WithPolicy returns the value nulled according to p.
+func (f Float) WithPolicy(p Policy) Float {
+	return floatFromValue(f.asValue().WithPolicy(p))
+}
+
+func (f *Float) applyPolicy(p Policy) {
+	*f = f.WithPolicy(p)
+}
+
 func (f Float) EncodeValues(key string, v *url.Values) error {
-	if !f.Valid {
-		return nil
-	}
+	return f.encodeValues(key, v, DefaultPolicy)
+}
 
-	v.Set(key, strconv.FormatFloat(f.Float64, 'f', -1, 64))
-	return nil
+func (f Float) encodeValues(key string, v *url.Values, p Policy) error {
+	return f.asValue().encodeValues(key, v, p)
 }
 
 func (f Float) MarshalJSON() ([]byte, error) {
-	if !f.Valid {
-		return []byte("null"), nil
-	}
-	return json.Marshal(f.Float64)
+	return f.marshalJSON(DefaultPolicy)
+}
+
+func (f Float) marshalJSON(p Policy) ([]byte, error) {
+	return f.asValue().marshalJSON(p)
 }
 
 func (f *Float) UnmarshalJSON(data []byte) error {
-	temp := null.Float{}
-	if err := json.Unmarshal(data, &temp); err != nil {
-		f.Valid = false
-		return err
-	}
-	f.Float64 = temp.Float64
-	f.Valid = temp.Valid
-	return nil
+	return f.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (f *Float) unmarshalJSON(data []byte, p Policy) error {
+	v := f.asValue()
+	err := v.unmarshalJSON(data, p)
+	*f = floatFromValue(v)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (f Float) MarshalText() ([]byte, error) {
+	return f.marshalText(DefaultPolicy)
+}
+
+func (f Float) marshalText(p Policy) ([]byte, error) {
+	return f.asValue().marshalText(p)
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
 func (f *Float) UnmarshalText(text []byte) error {
-	s := string(text)
-	if s == "" || s == "null" {
-		f.Valid = false
+	return f.unmarshalText(text, DefaultPolicy)
+}
+
+func (f *Float) unmarshalText(text []byte, p Policy) error {
+	v := f.asValue()
+	err := v.unmarshalText(text, p)
+	*f = floatFromValue(v)
+	return err
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (f Float) MarshalYAML() (any, error) {
+	return f.marshalYAML(DefaultPolicy)
+}
+
+func (f Float) marshalYAML(p Policy) (any, error) {
+	f = f.WithPolicy(p)
+	if !f.Valid {
+		return nil, nil
+	}
+	return f.Float64, nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (f *Float) UnmarshalYAML(value *yaml.Node) error {
+	return f.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (f *Float) unmarshalYAML(value *yaml.Node, p Policy) error {
+	if isYAMLNull(value) {
+		*f = NewFloat(0, false)
 		return nil
 	}
-	var err error
-	f.Float64, err = strconv.ParseFloat(s, 64)
-	if err != nil {
-		f.Valid = false
+	var v float64
+	if err := value.Decode(&v); err != nil {
 		return err
 	}
-	f.Valid = true
+	*f = NewFloat(v, true).WithPolicy(p)
 	return nil
 }
 
-func (f Float) GobEncode() ([]byte, error) {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	err := enc.Encode(f.Float64)
+// MarshalXML implements the xml.Marshaler interface.
+func (f Float) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	f = f.WithPolicy(DefaultPolicy)
+	return marshalXMLElement(e, start, strconv.FormatFloat(f.Float64, 'f', -1, 64), f.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (f *Float) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	err = enc.Encode(f.Valid)
-	if err != nil {
-		return nil, err
+	if !valid {
+		*f = NewFloat(0, false)
+		return nil
 	}
-	return buf.Bytes(), nil
+	return f.UnmarshalText([]byte(strings.TrimSpace(s)))
 }
 
-func (f *Float) GobDecode(data []byte) error {
-	buf := bytes.NewBuffer(data)
-	dec := gob.NewDecoder(buf)
-	err := dec.Decode(&f.Float64)
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (f Float) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	f = f.WithPolicy(DefaultPolicy)
+	if !f.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: strconv.FormatFloat(f.Float64, 'f', -1, 64)}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (f *Float) UnmarshalXMLAttr(attr xml.Attr) error {
+	return f.UnmarshalText([]byte(strings.TrimSpace(attr.Value)))
+}
+
+// Scan implements the sql.Scanner interface.
+// Besides float64 it accepts float32, any integer width, and numeric text in
+// string or []byte form, which is what MySQL and SQLite drivers hand back for
+// DECIMAL columns. Blank text is scanned as null.
+func (f *Float) Scan(value any) error {
+	return f.scan(value, DefaultPolicy)
+}
+
+func (f *Float) scan(value any, p Policy) error {
+	v := f.asValue()
+	err := v.scan(value, p)
+	*f = floatFromValue(v)
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (f Float) Value() (driver.Value, error) {
+	return f.value(DefaultPolicy)
+}
+
+func (f Float) value(p Policy) (driver.Value, error) {
+	return f.asValue().value(p)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (f Float) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(f.Valid)
+	if !f.Valid {
+		return b, nil
+	}
+	return floatCodec{}.EncodeBinary(b, f.Float64)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (f *Float) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*f = NewFloat(0, false)
+		return nil
+	}
+	x, err := floatCodec{}.DecodeBinary(p)
 	if err != nil {
 		return err
 	}
-	return dec.Decode(&f.Valid)
+	*f = NewFloat(x, true)
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (f Float) GobEncode() ([]byte, error) {
+	return f.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+// It also reads the format written by earlier versions of GobEncode.
+func (f *Float) GobDecode(data []byte) error {
+	if isLegacyGob(data) {
+		return decodeLegacyGob(data, &f.Float64, &f.Valid)
+	}
+	return f.UnmarshalBinary(data)
 }
 
 func (f Float) NullValue() null.Float {
@@ -108,3 +248,48 @@ func (f Float) NullValue() null.Float {
 	}
 	return null.NewFloat(0, false)
 }
+
+// floatCodec is the codec of Float.
+type floatCodec struct{ DefaultCodec[float64] }
+
+// UnmarshalJSON also accepts numeric strings.
+func (floatCodec) DecodeJSON(data []byte) (float64, bool, error) {
+	var n null.Float
+	if err := json.Unmarshal(data, &n); err != nil {
+		return 0, false, err
+	}
+	return n.Float64, n.Valid, nil
+}
+
+func (floatCodec) DecodeText(s string) (float64, bool, error) {
+	if s == "" || s == "null" {
+		return 0, false, nil
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return n, true, nil
+}
+
+func (floatCodec) Scan(src any) (float64, bool, error) {
+	return toFloat64(src)
+}
+
+func (floatCodec) Normalize(f float64, p Policy) (float64, bool) {
+	if p.ZeroAsNull && f == 0 {
+		return 0, false
+	}
+	return f, true
+}
+
+func (floatCodec) EncodeBinary(b []byte, f float64) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f)), nil
+}
+
+func (floatCodec) DecodeBinary(data []byte) (float64, error) {
+	if len(data) != 8 {
+		return 0, ErrInvalidBinary
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+}
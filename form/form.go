@@ -0,0 +1,264 @@
+// Package form decodes url.Values into structs. It is the inverse of the
+// EncodeValues methods of the nulled types and of
+// github.com/google/go-querystring/query, and reads the same `url` tags.
+//
+// Missing keys and empty values leave a field at its zero value, which is
+// null for every nulled type. Fields with a SetNull method, such as
+// nulled.Optional, are marked present and null for empty values instead, so
+// they can tell "?name=" apart from a missing name. Fields whose pointer implements
+// encoding.TextUnmarshaler, as all nulled types do, are decoded with
+// UnmarshalText; strings, booleans, integers, floats, pointers to them and
+// slices of them are decoded directly. Embedded structs are flattened and
+// other struct fields are read from "parent[child]" keys, the same as
+// go-querystring writes them.
+//
+// A `nulled` tag sets the null policy of a field, see nulled.ParsePolicy;
+// without one the package-wide nulled.DefaultPolicy applies.
+package form
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/hiscaler/nulled"
+)
+
+// ErrInvalidTarget is returned when Decode isn't given a non-nil pointer to
+// a struct.
+var ErrInvalidTarget = errors.New("form: target must be a non-nil pointer to a struct")
+
+// FieldError describes a field whose value couldn't be decoded.
+type FieldError struct {
+	Field string // Go field path, e.g. "Filter.Status"
+	Key   string // url.Values key
+	Value string // offending value
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("form: field %s (key %q, value %q): %v", e.Field, e.Key, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Errors is returned by Decode when one or more fields couldn't be decoded.
+// Every other field is still decoded.
+type Errors []*FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Field returns the error for the given Go field path, or nil.
+func (e Errors) Field(name string) *FieldError {
+	for _, err := range e {
+		if err.Field == name {
+			return err
+		}
+	}
+	return nil
+}
+
+// nuller is implemented by types that record whether a key was present,
+// such as nulled.Optional. An empty value marks them present and null
+// rather than leaving them at the zero value, which means absent.
+type nuller interface {
+	SetNull()
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// Decode fills the struct pointed to by dst from values.
+func Decode(values url.Values, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+	var errs Errors
+	decodeStruct(values, v.Elem(), "", "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// fieldKey returns the url.Values key of a struct field, following the rules
+// of go-querystring. It returns false for fields that must be skipped.
+func fieldKey(f reflect.StructField) (string, bool) {
+	tag := f.Tag.Get("url")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
+
+func decodeStruct(values url.Values, v reflect.Value, path, prefix string, errs *Errors) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		key, ok := fieldKey(f)
+		if !ok {
+			continue
+		}
+		fieldPath := f.Name
+		if path != "" {
+			fieldPath = path + "." + f.Name
+		}
+		fv := v.Field(i)
+
+		if isNested(f.Type) {
+			if f.Anonymous && f.Tag.Get("url") == "" {
+				decodeStruct(values, fv, path, prefix, errs)
+				continue
+			}
+			if f.IsExported() {
+				decodeStruct(values, fv, fieldPath, joinKey(prefix, key), errs)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		key = joinKey(prefix, key)
+		policy, err := fieldPolicy(f)
+		if err == nil {
+			err = decodeField(fv, values[key], policy)
+		}
+		if err != nil {
+			*errs = append(*errs, &FieldError{Field: fieldPath, Key: key, Value: values.Get(key), Err: err})
+		}
+	}
+}
+
+// fieldPolicy returns the policy from the `nulled` tag of f, or nil when f
+// has no such tag and its type's own decoding applies.
+func fieldPolicy(f reflect.StructField) (*nulled.Policy, error) {
+	if _, ok := f.Tag.Lookup("nulled"); !ok {
+		return nil, nil
+	}
+	p, err := nulled.FieldPolicy(f)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "[" + key + "]"
+}
+
+// isNested reports whether a field of type t is decoded field by field rather
+// than from a single value.
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func decodeField(v reflect.Value, vs []string, policy *nulled.Policy) error {
+	if v.Kind() == reflect.Slice && !v.Addr().Type().Implements(textUnmarshalerType) {
+		if len(vs) == 0 {
+			v.SetZero()
+			return nil
+		}
+		s := reflect.MakeSlice(v.Type(), len(vs), len(vs))
+		for i, value := range vs {
+			if err := decodeValue(s.Index(i), value, policy); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+
+	if len(vs) == 0 {
+		v.SetZero()
+		return nil
+	}
+	if vs[0] == "" {
+		if policy != nil && !policy.EmptyAsNull && v.Addr().Type().Implements(textUnmarshalerType) {
+			return decodeValue(v, "", policy)
+		}
+		if n, ok := v.Addr().Interface().(nuller); ok {
+			n.SetNull()
+			return nil
+		}
+		v.SetZero()
+		return nil
+	}
+	return decodeValue(v, vs[0], policy)
+}
+
+func decodeValue(v reflect.Value, s string, policy *nulled.Policy) error {
+	if v.Kind() == reflect.Pointer {
+		if s == "" {
+			v.SetZero()
+			return nil
+		}
+		p := reflect.New(v.Type().Elem())
+		if err := decodeValue(p.Elem(), s, policy); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if policy != nil {
+			return nulled.UnmarshalTextWithPolicy(u, []byte(s), *policy)
+		}
+		return u.UnmarshalText([]byte(s))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
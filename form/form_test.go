@@ -0,0 +1,186 @@
+package form
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/hiscaler/nulled"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type paging struct {
+	Page nulled.Int `url:"page"`
+	Size int        `url:"size,omitempty"`
+}
+
+type filter struct {
+	Status nulled.String `url:"status"`
+}
+
+type query struct {
+	paging
+	Name    nulled.String `url:"name"`
+	Age     nulled.Int    `url:"age"`
+	Active  nulled.Bool   `url:"active"`
+	Amount  nulled.Float  `url:"amount"`
+	Created nulled.Time   `url:"created"`
+	Tags    []string      `url:"tags"`
+	Limit   *int          `url:"limit"`
+	Filter  filter        `url:"filter"`
+	Skipped string        `url:"-"`
+	Plain   string
+}
+
+func TestDecode(t *testing.T) {
+	values := url.Values{
+		"page":           {"2"},
+		"size":           {"20"},
+		"name":           {"John Doe"},
+		"age":            {"30"},
+		"active":         {"1"},
+		"amount":         {"123.45"},
+		"created":        {"2023-01-15 10:30:00"},
+		"tags":           {"a", "b"},
+		"limit":          {"5"},
+		"filter[status]": {"open"},
+		"Skipped":        {"x"},
+		"-":              {"x"},
+		"Plain":          {"plain"},
+	}
+
+	var q query
+	require.NoError(t, Decode(values, &q))
+	limit := 5
+	assert.Equal(t, query{
+		paging:  paging{Page: nulled.IntFrom(2), Size: 20},
+		Name:    nulled.StringFrom("John Doe"),
+		Age:     nulled.IntFrom(30),
+		Active:  nulled.BoolFrom(true),
+		Amount:  nulled.FloatFrom(123.45),
+		Created: nulled.TimeFrom(time.Date(2023, 1, 15, 10, 30, 0, 0, time.UTC)),
+		Tags:    []string{"a", "b"},
+		Limit:   &limit,
+		Filter:  filter{Status: nulled.StringFrom("open")},
+		Plain:   "plain",
+	}, q)
+}
+
+func TestDecode_MissingAndEmpty(t *testing.T) {
+	q := query{
+		Name:  nulled.StringFrom("old"),
+		Age:   nulled.IntFrom(1),
+		Tags:  []string{"old"},
+		Plain: "old",
+	}
+	values := url.Values{
+		"name":   {""},
+		"active": {""},
+	}
+	require.NoError(t, Decode(values, &q))
+	assert.Equal(t, query{}, q)
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	in := query{
+		Name:    nulled.StringFrom("John"),
+		Age:     nulled.IntFrom(30),
+		Active:  nulled.BoolFrom(false),
+		Amount:  nulled.FloatFrom(0.5),
+		Created: nulled.TimeFrom(time.Date(2023, 1, 15, 10, 30, 0, 0, time.UTC)),
+	}
+	values := url.Values{}
+	require.NoError(t, in.Name.EncodeValues("name", &values))
+	require.NoError(t, in.Age.EncodeValues("age", &values))
+	require.NoError(t, in.Active.EncodeValues("active", &values))
+	require.NoError(t, in.Amount.EncodeValues("amount", &values))
+	require.NoError(t, in.Created.EncodeValues("created", &values))
+	require.NoError(t, in.Page.EncodeValues("page", &values))
+
+	var out query
+	require.NoError(t, Decode(values, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestDecode_FieldErrors(t *testing.T) {
+	values := url.Values{
+		"name":           {"ok"},
+		"age":            {"thirty"},
+		"limit":          {"x"},
+		"filter[status]": {"open"},
+		"page":           {"1.5"},
+	}
+	var q query
+	err := Decode(values, &q)
+	require.Error(t, err)
+
+	var errs Errors
+	require.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 3)
+	assert.NotNil(t, errs.Field("Page"))
+	assert.NotNil(t, errs.Field("Limit"))
+
+	ageErr := errs.Field("Age")
+	require.NotNil(t, ageErr)
+	assert.Equal(t, "age", ageErr.Key)
+	assert.Equal(t, "thirty", ageErr.Value)
+	assert.ErrorIs(t, ageErr, strconv.ErrSyntax)
+
+	// the other fields are still decoded
+	assert.Equal(t, nulled.StringFrom("ok"), q.Name)
+	assert.Equal(t, nulled.StringFrom("open"), q.Filter.Status)
+}
+
+func TestDecode_InvalidTarget(t *testing.T) {
+	var q query
+	assert.ErrorIs(t, Decode(url.Values{}, q), ErrInvalidTarget)
+	assert.ErrorIs(t, Decode(url.Values{}, (*query)(nil)), ErrInvalidTarget)
+	var i int
+	assert.ErrorIs(t, Decode(url.Values{}, &i), ErrInvalidTarget)
+}
+
+func TestDecode_Optional(t *testing.T) {
+	var p struct {
+		Name nulled.OptionalString `url:"name"`
+		Age  nulled.OptionalInt    `url:"age"`
+		Tag  nulled.OptionalString `url:"tag"`
+	}
+	require.NoError(t, Decode(url.Values{"name": {""}, "age": {"3"}}, &p))
+	assert.True(t, p.Name.Present)
+	assert.True(t, p.Name.IsNull())
+	assert.Equal(t, nulled.OptionalFrom(nulled.IntFrom(3)), p.Age)
+	assert.False(t, p.Tag.Present)
+}
+
+func TestDecode_Policy(t *testing.T) {
+	var p struct {
+		Code  nulled.String                                        `url:"code" nulled:""`
+		Stock nulled.Int                                           `url:"stock" nulled:"zero"`
+		Name  nulled.String                                        `url:"name"`
+		Price nulled.Decimal                                       `url:"price" nulled:"zero"`
+		Qty   nulled.Policied[nulled.Int, nulled.PolicyZeroAsNull] `url:"qty"`
+		Bad   nulled.Int                                           `url:"bad" nulled:"bogus"`
+	}
+	values := url.Values{"code": {" "}, "stock": {"0"}, "name": {" x "}, "price": {"0.00"}, "qty": {"0"}}
+	err := Decode(values, &p)
+
+	var errs Errors
+	require.True(t, errors.As(err, &errs))
+	assert.Len(t, errs, 1)
+	assert.NotNil(t, errs.Field("Bad"))
+
+	assert.Equal(t, nulled.NewString(" ", true), p.Code)
+	assert.False(t, p.Stock.Valid)
+	assert.False(t, p.Price.Valid)
+	assert.False(t, p.Qty.V.Valid)
+	assert.Equal(t, nulled.StringFrom("x"), p.Name)
+
+	var empty struct {
+		Code nulled.String `url:"code" nulled:""`
+	}
+	require.NoError(t, Decode(url.Values{"code": {""}}, &empty))
+	assert.Equal(t, nulled.NewString("", true), empty.Code)
+}
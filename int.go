@@ -1,13 +1,16 @@
 package nulled
 
 import (
-	"bytes"
-	"encoding/gob"
+	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
+	"encoding/xml"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
 )
 
 type Int null.Int
@@ -16,15 +19,16 @@ func NewInt(i int64, valid bool) Int {
 	return Int(null.NewInt(i, valid))
 }
 
+// IntFrom creates an Int from i under DefaultPolicy.
 func IntFrom(i int64) Int {
-	return NewInt(i, true)
+	return NewInt(i, true).WithPolicy(DefaultPolicy)
 }
 
 func IntFromPtr(i *int64) Int {
 	if i == nil {
 		return NewInt(0, false)
 	}
-	return NewInt(*i, true)
+	return IntFrom(*i)
 }
 
 func (i Int) ValueOrZero() int64 {
@@ -34,78 +38,266 @@ func (i Int) ValueOrZero() int64 {
 	return i.Int64
 }
 
+// IsZero reports whether the value is null.
+func (i Int) IsZero() bool {
+	return !i.Valid
+}
+
+// asValue returns i as the Value it is built on.
+func (i Int) asValue() Value[int64] {
+	return NewValue(i.Int64, i.Valid)
+}
+
+func intFromValue(v Value[int64]) Int {
+	return NewInt(v.V, v.Valid)
+}
+
+// WithPolicy returns the value nulled according to p.
+func (i Int) WithPolicy(p Policy) Int {
+	return intFromValue(i.asValue().WithPolicy(p))
+}
+
+func (i *Int) applyPolicy(p Policy) {
+	*i = i.WithPolicy(p)
+}
+
 func (i Int) EncodeValues(key string, v *url.Values) error {
-	if !i.Valid {
-		return nil
-	}
+	return i.encodeValues(key, v, DefaultPolicy)
+}
 
-	v.Set(key, strconv.FormatInt(i.Int64, 10))
-	return nil
+func (i Int) encodeValues(key string, v *url.Values, p Policy) error {
+	return i.asValue().encodeValues(key, v, p)
 }
 
 func (i Int) MarshalJSON() ([]byte, error) {
+	return i.marshalJSON(DefaultPolicy)
+}
+
+func (i Int) marshalJSON(p Policy) ([]byte, error) {
+	return i.asValue().marshalJSON(p)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (i Int) MarshalYAML() (any, error) {
+	return i.marshalYAML(DefaultPolicy)
+}
+
+func (i Int) marshalYAML(p Policy) (any, error) {
+	i = i.WithPolicy(p)
 	if !i.Valid {
-		return []byte(`null`), nil
+		return nil, nil
 	}
+	return i.Int64, nil
+}
 
-	return []byte(strconv.FormatInt(i.Int64, 10)), nil
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (i *Int) UnmarshalYAML(value *yaml.Node) error {
+	return i.unmarshalYAML(value, DefaultPolicy)
 }
 
-// GobEncode implements the gob.GobEncoder interface.
-func (i Int) GobEncode() ([]byte, error) {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	err := enc.Encode(i.Int64)
-	if err != nil {
-		return nil, err
+func (i *Int) unmarshalYAML(value *yaml.Node, p Policy) error {
+	if isYAMLNull(value) {
+		*i = NewInt(0, false)
+		return nil
 	}
-	err = enc.Encode(i.Valid)
-	if err != nil {
-		return nil, err
+	var v int64
+	if err := value.Decode(&v); err != nil {
+		return err
 	}
-	return buf.Bytes(), nil
+	*i = NewInt(v, true).WithPolicy(p)
+	return nil
 }
 
-// GobDecode implements the gob.GobDecoder interface.
-func (i *Int) GobDecode(data []byte) error {
-	buf := bytes.NewBuffer(data)
-	dec := gob.NewDecoder(buf)
-	err := dec.Decode(&i.Int64)
+// MarshalXML implements the xml.Marshaler interface.
+func (i Int) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	i = i.WithPolicy(DefaultPolicy)
+	return marshalXMLElement(e, start, strconv.FormatInt(i.Int64, 10), i.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (i *Int) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
 	if err != nil {
 		return err
 	}
-	return dec.Decode(&i.Valid)
+	if !valid {
+		*i = NewInt(0, false)
+		return nil
+	}
+	return i.UnmarshalText([]byte(strings.TrimSpace(s)))
 }
 
-func (i *Int) UnmarshalJSON(bytes []byte) error {
-	// 通过类型转换获取值
-	n := null.Int(*i)
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (i Int) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	i = i.WithPolicy(DefaultPolicy)
+	if !i.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: strconv.FormatInt(i.Int64, 10)}, nil
+}
 
-	// 处理空字符串或 null 的情况
-	if string(bytes) == `""` || string(bytes) == `null` || string(bytes) == `nil` {
-		n.Valid = false
-		n.Int64 = 0
-		*i = Int(n)
-		return nil
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (i *Int) UnmarshalXMLAttr(attr xml.Attr) error {
+	return i.UnmarshalText([]byte(strings.TrimSpace(attr.Value)))
+}
+
+// Scan implements the sql.Scanner interface.
+// Besides int64 it accepts any integer width, integral floats, and decimal
+// text in string or []byte form, which is what MySQL and SQLite drivers hand
+// back for BIGINT and DECIMAL columns. Blank text is scanned as null.
+func (i *Int) Scan(value any) error {
+	return i.scan(value, DefaultPolicy)
+}
+
+func (i *Int) scan(value any, p Policy) error {
+	v := i.asValue()
+	err := v.scan(value, p)
+	*i = intFromValue(v)
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+func (i Int) Value() (driver.Value, error) {
+	return i.value(DefaultPolicy)
+}
+
+func (i Int) value(p Policy) (driver.Value, error) {
+	return i.asValue().value(p)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (i Int) MarshalText() ([]byte, error) {
+	return i.marshalText(DefaultPolicy)
+}
+
+func (i Int) marshalText(p Policy) ([]byte, error) {
+	return i.asValue().marshalText(p)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (i *Int) UnmarshalText(text []byte) error {
+	return i.unmarshalText(text, DefaultPolicy)
+}
+
+func (i *Int) unmarshalText(text []byte, p Policy) error {
+	v := i.asValue()
+	err := v.unmarshalText(text, p)
+	*i = intFromValue(v)
+	return err
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (i Int) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(i.Valid)
+	if !i.Valid {
+		return b, nil
 	}
+	return intCodec{}.EncodeBinary(b, i.Int64)
+}
 
-	// 解析数值
-	err := json.Unmarshal(bytes, &n.Int64)
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (i *Int) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
 	if err != nil {
-		n.Valid = false
-		n.Int64 = 0
-		*i = Int(n)
 		return err
 	}
-
-	n.Valid = true
-	*i = Int(n)
+	if !valid {
+		*i = NewInt(0, false)
+		return nil
+	}
+	x, err := intCodec{}.DecodeBinary(p)
+	if err != nil {
+		return err
+	}
+	*i = NewInt(x, true)
 	return nil
 }
 
+// GobEncode implements the gob.GobEncoder interface.
+func (i Int) GobEncode() ([]byte, error) {
+	return i.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+// It also reads the format written by earlier versions of GobEncode.
+func (i *Int) GobDecode(data []byte) error {
+	if isLegacyGob(data) {
+		return decodeLegacyGob(data, &i.Int64, &i.Valid)
+	}
+	return i.UnmarshalBinary(data)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Besides numbers and null it accepts "" as null.
+func (i *Int) UnmarshalJSON(data []byte) error {
+	return i.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (i *Int) unmarshalJSON(data []byte, p Policy) error {
+	v := i.asValue()
+	err := v.unmarshalJSON(data, p)
+	*i = intFromValue(v)
+	return err
+}
+
 func (i Int) NullValue() null.Int {
 	if i.Valid {
 		return null.IntFrom(i.Int64)
 	}
 	return null.NewInt(0, false)
 }
+
+// intCodec is the codec of Int.
+type intCodec struct{ DefaultCodec[int64] }
+
+func (intCodec) EncodeJSON(n int64) ([]byte, error) {
+	return strconv.AppendInt(nil, n, 10), nil
+}
+
+func (intCodec) DecodeJSON(data []byte) (int64, bool, error) {
+	if string(data) == `""` || string(data) == `nil` {
+		return 0, false, nil
+	}
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return 0, false, err
+	}
+	return n, true, nil
+}
+
+func (intCodec) DecodeText(s string) (int64, bool, error) {
+	if s == "" || s == "null" {
+		return 0, false, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return n, true, nil
+}
+
+func (intCodec) Scan(src any) (int64, bool, error) {
+	return toInt64(src)
+}
+
+func (intCodec) Normalize(n int64, p Policy) (int64, bool) {
+	if p.ZeroAsNull && n == 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+func (intCodec) EncodeBinary(b []byte, n int64) ([]byte, error) {
+	return binary.AppendVarint(b, n), nil
+}
+
+func (intCodec) DecodeBinary(data []byte) (int64, error) {
+	n, size := binary.Varint(data)
+	if size <= 0 || size != len(data) {
+		return 0, ErrInvalidBinary
+	}
+	return n, nil
+}
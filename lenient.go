@@ -0,0 +1,151 @@
+package nulled
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// LenientInt, LenientFloat, LenientBool and LenientString are opt-in
+// variants for loosely typed upstream APIs, e.g. PHP backends that send
+// numbers as strings. They embed the regular type, so they encode exactly
+// like it and expose the same fields and methods, but their UnmarshalJSON
+// coerces other JSON representations instead of rejecting them:
+//
+//	LenientInt     123, "123", 12.0, "12.00", true/false, "" (null)
+//	LenientFloat   12.5, "12.50", true/false, "" (null)
+//	LenientBool    true, 1/0, "1"/"0", "true", "yes"/"no", "on"/"off", "y"/"n", "" (null)
+//	LenientString  "abc", numbers and booleans as their literal text
+//
+// Only input that can't be interpreted at all, such as objects, arrays or
+// "abc" for a number, is reported as an error, wrapped in a ScanError.
+type (
+	LenientInt struct {
+		Int
+	}
+	LenientFloat struct {
+		Float
+	}
+	LenientBool struct {
+		Bool
+	}
+	LenientString struct {
+		String
+	}
+)
+
+// decodeLenientJSON decodes a JSON scalar into nil, a string, a bool or a
+// json.Number holding the literal text of a number.
+func decodeLenientJSON(data []byte, typ string) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	switch v.(type) {
+	case nil, string, bool, json.Number:
+		return v, nil
+	}
+	return nil, &ScanError{Src: string(data), Type: typ, Err: ErrUnsupportedType}
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (i *LenientInt) UnmarshalJSON(data []byte) error {
+	return i.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (i *LenientInt) unmarshalJSON(data []byte, p Policy) error {
+	v, err := decodeLenientJSON(data, "Int")
+	if err != nil {
+		i.Int = NewInt(0, false)
+		return err
+	}
+	if n, ok := v.(json.Number); ok {
+		v = string(n)
+	}
+	n, valid, err := toInt64(v)
+	if err != nil {
+		i.Int = NewInt(0, false)
+		return err
+	}
+	i.Int = NewInt(n, valid).WithPolicy(p)
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (f *LenientFloat) UnmarshalJSON(data []byte) error {
+	return f.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (f *LenientFloat) unmarshalJSON(data []byte, p Policy) error {
+	v, err := decodeLenientJSON(data, "Float")
+	if err != nil {
+		f.Float = NewFloat(0, false)
+		return err
+	}
+	switch b := v.(type) {
+	case json.Number:
+		v = string(b)
+	case bool:
+		// toFloat64 leaves booleans to toBool, accept them here as 1 and 0
+		v = 0
+		if b {
+			v = 1
+		}
+	}
+	n, valid, err := toFloat64(v)
+	if err != nil {
+		f.Float = NewFloat(0, false)
+		return err
+	}
+	f.Float = NewFloat(n, valid).WithPolicy(p)
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *LenientBool) UnmarshalJSON(data []byte) error {
+	return b.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (b *LenientBool) unmarshalJSON(data []byte, p Policy) error {
+	v, err := decodeLenientJSON(data, "Bool")
+	if err != nil {
+		b.Bool = NewBool(false, false)
+		return err
+	}
+	if n, ok := v.(json.Number); ok {
+		v = string(n)
+	}
+	bo, valid, err := toBool(v)
+	if err != nil {
+		b.Bool = NewBool(false, false)
+		return err
+	}
+	b.Bool = NewBool(bo, valid).WithPolicy(p)
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *LenientString) UnmarshalJSON(data []byte) error {
+	return m.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (m *LenientString) unmarshalJSON(data []byte, p Policy) error {
+	v, err := decodeLenientJSON(data, "String")
+	if err != nil {
+		m.String = NewString("", false)
+		return err
+	}
+	switch s := v.(type) {
+	case nil:
+		m.String = NewString("", false)
+	case string:
+		m.String = NewString(s, true).WithPolicy(p)
+	case json.Number:
+		m.String = NewString(string(s), true)
+	case bool:
+		m.String = NewString(strconv.FormatBool(s), true)
+	}
+	return nil
+}
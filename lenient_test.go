@@ -0,0 +1,181 @@
+package nulled
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLenientInt_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Int
+		wantErr bool
+	}{
+		{input: `123`, want: IntFrom(123)},
+		{input: `"123"`, want: IntFrom(123)},
+		{input: `" -7 "`, want: IntFrom(-7)},
+		{input: `12.0`, want: IntFrom(12)},
+		{input: `"12.00"`, want: IntFrom(12)},
+		{input: `true`, want: IntFrom(1)},
+		{input: `""`, want: NewInt(0, false)},
+		{input: `null`, want: NewInt(0, false)},
+		{input: `9223372036854775807`, want: IntFrom(9223372036854775807)},
+		{input: `9223372036854775808`, wantErr: true},
+		{input: `12.5`, wantErr: true},
+		{input: `"abc"`, wantErr: true},
+		{input: `{}`, wantErr: true},
+		{input: `[1]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var i LenientInt
+			err := json.Unmarshal([]byte(tt.input), &i)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, i.Int)
+		})
+	}
+}
+
+func TestLenientFloat_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Float
+		wantErr bool
+	}{
+		{input: `12.5`, want: FloatFrom(12.5)},
+		{input: `"12.50"`, want: FloatFrom(12.5)},
+		{input: `"1e3"`, want: FloatFrom(1000)},
+		{input: `3`, want: FloatFrom(3)},
+		{input: `false`, want: FloatFrom(0)},
+		{input: `""`, want: NewFloat(0, false)},
+		{input: `" "`, want: NewFloat(0, false)},
+		{input: `null`, want: NewFloat(0, false)},
+		{input: `"12,50"`, wantErr: true},
+		{input: `{"a":1}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var f LenientFloat
+			err := json.Unmarshal([]byte(tt.input), &f)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.Float)
+		})
+	}
+}
+
+func TestLenientBool_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Bool
+		wantErr error
+	}{
+		{input: `true`, want: BoolFrom(true)},
+		{input: `false`, want: BoolFrom(false)},
+		{input: `1`, want: BoolFrom(true)},
+		{input: `0`, want: BoolFrom(false)},
+		{input: `"1"`, want: BoolFrom(true)},
+		{input: `"0"`, want: BoolFrom(false)},
+		{input: `"true"`, want: BoolFrom(true)},
+		{input: `"yes"`, want: BoolFrom(true)},
+		{input: `"No"`, want: BoolFrom(false)},
+		{input: `"on"`, want: BoolFrom(true)},
+		{input: `""`, want: NewBool(false, false)},
+		{input: `null`, want: NewBool(false, false)},
+		{input: `2`, wantErr: strconv.ErrSyntax},
+		{input: `"maybe"`, wantErr: strconv.ErrSyntax},
+		{input: `[]`, wantErr: ErrUnsupportedType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var b LenientBool
+			err := json.Unmarshal([]byte(tt.input), &b)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, b.Bool)
+		})
+	}
+}
+
+func TestLenientString_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    String
+		wantErr bool
+	}{
+		{input: `"abc"`, want: StringFrom("abc")},
+		{input: `123`, want: StringFrom("123")},
+		{input: `12.50`, want: StringFrom("12.50")},
+		{input: `true`, want: StringFrom("true")},
+		{input: `""`, want: NewString("", false)},
+		{input: `null`, want: NewString("", false)},
+		{input: `{}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var s LenientString
+			err := json.Unmarshal([]byte(tt.input), &s)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, s.String)
+		})
+	}
+}
+
+func TestLenient_Struct(t *testing.T) {
+	var order struct {
+		ID     LenientInt    `json:"id"`
+		Price  LenientFloat  `json:"price"`
+		Paid   LenientBool   `json:"paid"`
+		SKU    LenientString `json:"sku"`
+		Coupon LenientFloat  `json:"coupon"`
+	}
+	data := []byte(`{"id":"1001","price":"12.50","paid":"1","sku":12345,"coupon":""}`)
+	require.NoError(t, json.Unmarshal(data, &order))
+	assert.Equal(t, int64(1001), order.ID.Int64)
+	assert.Equal(t, 12.5, order.Price.Float64)
+	assert.True(t, order.Paid.Bool.Bool)
+	assert.Equal(t, "12345", order.SKU.ValueOrZero())
+	assert.False(t, order.Coupon.Valid)
+
+	// encoding is the same as the regular types
+	out, err := json.Marshal(order)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":1001,"price":12.5,"paid":true,"sku":"12345","coupon":null}`, string(out))
+}
+
+func TestLenient_ResetOnError(t *testing.T) {
+	i := LenientInt{IntFrom(9)}
+	f := LenientFloat{FloatFrom(1.5)}
+	b := LenientBool{BoolFrom(true)}
+	s := LenientString{StringFrom("abc")}
+	for _, u := range []json.Unmarshaler{&i, &f, &b, &s} {
+		assert.Error(t, u.UnmarshalJSON([]byte(`{`)))
+		assert.Error(t, u.UnmarshalJSON([]byte(`[1]`)))
+	}
+	assert.False(t, i.Valid)
+	assert.False(t, f.Valid)
+	assert.False(t, b.Valid)
+	assert.False(t, s.Valid)
+}
@@ -0,0 +1,184 @@
+package nulled
+
+import "encoding/json"
+
+// Nullable numbers of every size. They are Values, so they share the fields
+// V and Valid and the methods of Value, and are range checked when decoded:
+// text, JSON numbers and database values that don't fit the type are
+// reported as a ScanError wrapping strconv.ErrRange instead of being
+// truncated. JSON strings other than "" (null) are rejected, as for Int.
+//
+// The binary form of the signed integers is a varint, like Int, that of the
+// unsigned ones a uvarint, and that of Float32 the same as Float. Unsigned
+// values beyond the int64 range are written to databases as decimal text.
+type (
+	Int32   = Value[int32]
+	Int16   = Value[int16]
+	Int8    = Value[int8]
+	Uint    = Value[uint]
+	Uint64  = Value[uint64]
+	Uint32  = Value[uint32]
+	Uint16  = Value[uint16]
+	Uint8   = Value[uint8]
+	Float32 = Value[float32]
+)
+
+func NewInt32(i int32, valid bool) Int32 {
+	return NewValue(i, valid)
+}
+
+// Int32From creates an Int32 from i under DefaultPolicy.
+func Int32From(i int32) Int32 {
+	return ValueFrom(i)
+}
+
+func Int32FromPtr(i *int32) Int32 {
+	return ValueFromPtr(i)
+}
+
+func NewInt16(i int16, valid bool) Int16 {
+	return NewValue(i, valid)
+}
+
+// Int16From creates an Int16 from i under DefaultPolicy.
+func Int16From(i int16) Int16 {
+	return ValueFrom(i)
+}
+
+func Int16FromPtr(i *int16) Int16 {
+	return ValueFromPtr(i)
+}
+
+func NewInt8(i int8, valid bool) Int8 {
+	return NewValue(i, valid)
+}
+
+// Int8From creates an Int8 from i under DefaultPolicy.
+func Int8From(i int8) Int8 {
+	return ValueFrom(i)
+}
+
+func Int8FromPtr(i *int8) Int8 {
+	return ValueFromPtr(i)
+}
+
+func NewUint(u uint, valid bool) Uint {
+	return NewValue(u, valid)
+}
+
+// UintFrom creates an Uint from u under DefaultPolicy.
+func UintFrom(u uint) Uint {
+	return ValueFrom(u)
+}
+
+func UintFromPtr(u *uint) Uint {
+	return ValueFromPtr(u)
+}
+
+func NewUint64(u uint64, valid bool) Uint64 {
+	return NewValue(u, valid)
+}
+
+// Uint64From creates an Uint64 from u under DefaultPolicy.
+func Uint64From(u uint64) Uint64 {
+	return ValueFrom(u)
+}
+
+func Uint64FromPtr(u *uint64) Uint64 {
+	return ValueFromPtr(u)
+}
+
+func NewUint32(u uint32, valid bool) Uint32 {
+	return NewValue(u, valid)
+}
+
+// Uint32From creates an Uint32 from u under DefaultPolicy.
+func Uint32From(u uint32) Uint32 {
+	return ValueFrom(u)
+}
+
+func Uint32FromPtr(u *uint32) Uint32 {
+	return ValueFromPtr(u)
+}
+
+func NewUint16(u uint16, valid bool) Uint16 {
+	return NewValue(u, valid)
+}
+
+// Uint16From creates an Uint16 from u under DefaultPolicy.
+func Uint16From(u uint16) Uint16 {
+	return ValueFrom(u)
+}
+
+func Uint16FromPtr(u *uint16) Uint16 {
+	return ValueFromPtr(u)
+}
+
+func NewUint8(u uint8, valid bool) Uint8 {
+	return NewValue(u, valid)
+}
+
+// Uint8From creates an Uint8 from u under DefaultPolicy.
+func Uint8From(u uint8) Uint8 {
+	return ValueFrom(u)
+}
+
+func Uint8FromPtr(u *uint8) Uint8 {
+	return ValueFromPtr(u)
+}
+
+func NewFloat32(f float32, valid bool) Float32 {
+	return NewValue(f, valid)
+}
+
+// Float32From creates a Float32 from f under DefaultPolicy.
+func Float32From(f float32) Float32 {
+	return ValueFrom(f)
+}
+
+func Float32FromPtr(f *float32) Float32 {
+	return ValueFromPtr(f)
+}
+
+// numberCodec is the codec of the sized numbers. It reports errors under
+// the name of the nulled type.
+type numberCodec[T any] struct {
+	DefaultCodec[T]
+	name string
+}
+
+// rename reports err under the name of the nulled type.
+func (c numberCodec[T]) rename(v T, valid bool, err error) (T, bool, error) {
+	if scanErr, ok := err.(*ScanError); ok {
+		scanErr.Type = c.name
+	}
+	return v, valid, err
+}
+
+// DecodeJSON accepts numbers, and "" as null.
+func (c numberCodec[T]) DecodeJSON(data []byte) (T, bool, error) {
+	var zero T
+	v, err := decodeLenientJSON(data, c.name)
+	if err != nil {
+		return zero, false, err
+	}
+	switch s := v.(type) {
+	case nil:
+		return zero, false, nil
+	case json.Number:
+		return c.DecodeText(string(s))
+	case string:
+		if s == "" {
+			return zero, false, nil
+		}
+	}
+	return zero, false, &ScanError{Src: v, Type: c.name, Err: ErrUnsupportedType}
+}
+
+func (c numberCodec[T]) DecodeText(s string) (T, bool, error) {
+	return c.rename(c.DefaultCodec.DecodeText(s))
+}
+
+func (c numberCodec[T]) Scan(src any) (T, bool, error) {
+	return c.rename(c.DefaultCodec.Scan(src))
+}
@@ -0,0 +1,220 @@
+package nulled
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumeric_Constructors(t *testing.T) {
+	assert.Equal(t, Int32{V: 5, Valid: true}, NewInt32(5, true))
+	assert.Equal(t, Int32{}, NewInt32(0, false).WithPolicy(DefaultPolicy))
+	assert.Equal(t, Uint64{V: math.MaxUint64, Valid: true}, Uint64From(math.MaxUint64))
+	assert.Equal(t, Float32{V: 1.5, Valid: true}, Float32From(1.5))
+
+	i := int8(-3)
+	assert.Equal(t, Int8From(-3), Int8FromPtr(&i))
+	assert.False(t, Int8FromPtr(nil).Valid)
+	assert.Equal(t, int8(-3), Int8FromPtr(&i).ValueOrZero())
+	assert.Equal(t, uint16(0), NewUint16(7, false).ValueOrZero())
+}
+
+func TestNumeric_JSON(t *testing.T) {
+	type row struct {
+		A Int32   `json:"a"`
+		B Int16   `json:"b"`
+		C Int8    `json:"c"`
+		D Uint    `json:"d"`
+		E Uint64  `json:"e"`
+		F Uint32  `json:"f"`
+		G Uint16  `json:"g"`
+		H Uint8   `json:"h"`
+		I Float32 `json:"i"`
+	}
+
+	in := row{
+		A: Int32From(math.MinInt32),
+		B: Int16From(math.MaxInt16),
+		C: Int8From(-1),
+		D: UintFrom(7),
+		E: Uint64From(math.MaxUint64),
+		F: Uint32From(math.MaxUint32),
+		G: NewUint16(0, false),
+		H: Uint8From(255),
+		I: Float32From(0.1),
+	}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":-2147483648,"b":32767,"c":-1,"d":7,"e":18446744073709551615,"f":4294967295,"g":null,"h":255,"i":0.1}`, string(data))
+
+	var out row
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"a":"","h":null}`), &out))
+	assert.False(t, out.A.Valid)
+	assert.False(t, out.H.Valid)
+}
+
+func TestNumeric_UnmarshalJSONErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		value json.Unmarshaler
+		want  error
+	}{
+		{name: "int8 overflow", input: `128`, value: new(Int8), want: strconv.ErrRange},
+		{name: "int32 underflow", input: `-2147483649`, value: new(Int32), want: strconv.ErrRange},
+		{name: "uint8 overflow", input: `256`, value: new(Uint8), want: strconv.ErrRange},
+		{name: "uint64 overflow", input: `18446744073709551616`, value: new(Uint64), want: strconv.ErrRange},
+		{name: "negative uint", input: `-1`, value: new(Uint), want: strconv.ErrSyntax},
+		{name: "fraction", input: `1.5`, value: new(Int16), want: strconv.ErrSyntax},
+		{name: "float32 overflow", input: `1e39`, value: new(Float32), want: strconv.ErrRange},
+		{name: "string", input: `"12"`, value: new(Int32), want: ErrUnsupportedType},
+		{name: "bool", input: `true`, value: new(Uint32), want: ErrUnsupportedType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.value.UnmarshalJSON([]byte(tt.input))
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	var i Int8
+	err := i.UnmarshalJSON([]byte(`300`))
+	var scanErr *ScanError
+	require.ErrorAs(t, err, &scanErr)
+	assert.Equal(t, "Int8", scanErr.Type)
+}
+
+func TestNumeric_Text(t *testing.T) {
+	var u Uint64
+	require.NoError(t, u.UnmarshalText([]byte(" 18446744073709551615 ")))
+	assert.Equal(t, Uint64From(math.MaxUint64), u)
+
+	text, err := u.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "18446744073709551615", string(text))
+
+	var f Float32
+	require.NoError(t, f.UnmarshalText([]byte("0.1")))
+	text, err = f.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "0.1", string(text))
+
+	var i Int16
+	assert.ErrorIs(t, i.UnmarshalText([]byte("40000")), strconv.ErrRange)
+	assert.False(t, i.Valid)
+	require.NoError(t, i.UnmarshalText([]byte("")))
+	assert.False(t, i.Valid)
+}
+
+func TestNumeric_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, Uint64From(math.MaxUint64).EncodeValues("id", &v))
+	require.NoError(t, Int8From(-8).EncodeValues("delta", &v))
+	require.NoError(t, Float32From(2.5).EncodeValues("ratio", &v))
+	require.NoError(t, NewInt32(0, false).EncodeValues("skip", &v))
+	assert.Equal(t, url.Values{"id": {"18446744073709551615"}, "delta": {"-8"}, "ratio": {"2.5"}}, v)
+}
+
+func TestNumeric_Scan(t *testing.T) {
+	var u Uint64
+	require.NoError(t, u.Scan([]byte("18446744073709551615")))
+	assert.Equal(t, Uint64From(math.MaxUint64), u)
+	require.NoError(t, u.Scan(int64(42)))
+	assert.Equal(t, Uint64From(42), u)
+
+	var u8 Uint8
+	assert.ErrorIs(t, u8.Scan(int64(256)), strconv.ErrRange)
+	assert.ErrorIs(t, u8.Scan(int64(-1)), strconv.ErrRange)
+	require.NoError(t, u8.Scan("12.00"))
+	assert.Equal(t, Uint8From(12), u8)
+
+	var i Int32
+	require.NoError(t, i.Scan(int64(math.MaxInt32)))
+	assert.Equal(t, Int32From(math.MaxInt32), i)
+	err := i.Scan(int64(math.MaxInt32 + 1))
+	assert.ErrorIs(t, err, strconv.ErrRange)
+	assert.Equal(t, Int32From(math.MaxInt32), i, "unchanged on error")
+	var scanErr *ScanError
+	require.ErrorAs(t, err, &scanErr)
+	assert.Equal(t, "Int32", scanErr.Type)
+	require.NoError(t, i.Scan(nil))
+	assert.False(t, i.Valid)
+
+	var f Float32
+	require.NoError(t, f.Scan(0.1))
+	assert.Equal(t, Float32From(0.1), f)
+	assert.ErrorIs(t, f.Scan(1e39), strconv.ErrRange)
+}
+
+func TestNumeric_Value(t *testing.T) {
+	v, err := Int8From(-8).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(-8), v)
+
+	v, err = Uint32From(math.MaxUint32).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(math.MaxUint32), v)
+
+	v, err = Uint64From(math.MaxUint64).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "18446744073709551615", v)
+
+	v, err = Float32From(0.1).Value()
+	require.NoError(t, err)
+	assert.Equal(t, 0.1, v)
+
+	v, err = NewUint(0, false).Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestNumeric_SQL(t *testing.T) {
+	db := openTestDB(t)
+
+	_, err := db.Exec("INSERT", Int16From(-300), Uint64From(math.MaxUint64), Float32From(1.1), NewUint8(0, false))
+	require.NoError(t, err)
+
+	var (
+		i  Int16
+		u  Uint64
+		f  Float32
+		u8 = Uint8From(1)
+	)
+	require.NoError(t, db.QueryRow("SELECT").Scan(&i, &u, &f, &u8))
+	assert.Equal(t, Int16From(-300), i)
+	assert.Equal(t, Uint64From(math.MaxUint64), u)
+	assert.Equal(t, Float32From(1.1), f)
+	assert.False(t, u8.Valid)
+}
+
+func TestNumeric_Binary(t *testing.T) {
+	assertBinaryRoundTrip(t, Int8From(-3), []byte{1, 5})
+	assertBinaryRoundTrip(t, Int32From(math.MinInt32), nil)
+	assertBinaryRoundTrip(t, Uint64From(math.MaxUint64), nil)
+	assertBinaryRoundTrip(t, Uint16From(300), []byte{1, 0xac, 0x02})
+	assertBinaryRoundTrip(t, Float32From(1), []byte{1, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0})
+	assertBinaryRoundTrip(t, NewUint(0, false), []byte{0})
+
+	var u Uint8
+	assert.ErrorIs(t, u.UnmarshalBinary([]byte{1, 0xac, 0x02}), ErrInvalidBinary)
+}
+
+func TestNumeric_Policy(t *testing.T) {
+	zero := Policy{ZeroAsNull: true}
+	assert.False(t, Int32From(0).WithPolicy(zero).Valid)
+	assert.False(t, Float32From(0).WithPolicy(zero).Valid)
+	assert.True(t, Uint8From(1).WithPolicy(zero).Valid)
+
+	var u Uint
+	require.NoError(t, UnmarshalTextWithPolicy(&u, []byte("0"), zero))
+	assert.False(t, u.Valid)
+}
@@ -0,0 +1,187 @@
+package nulled
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Optional wraps a nulled value and records whether it was present in the
+// decoded input at all, which PATCH style endpoints need to tell
+// `{"name": null}` (clear the field) apart from a missing "name" (leave it
+// alone):
+//
+//	missing key      Present == false
+//	null             Present == true, Value is null
+//	value            Present == true, Value is valid
+//
+// T is expected to be one of the nulled types.
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+type (
+	OptionalString = Optional[String]
+	OptionalInt    = Optional[Int]
+	OptionalFloat  = Optional[Float]
+	OptionalBool   = Optional[Bool]
+	OptionalTime   = Optional[Time]
+)
+
+// OptionalFrom creates a present Optional holding v.
+func OptionalFrom[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Present: true}
+}
+
+// IsNull reports whether the value is present and null.
+func (o Optional[T]) IsNull() bool {
+	if !o.Present {
+		return false
+	}
+	z, ok := any(o.Value).(interface{ IsZero() bool })
+	return ok && z.IsZero()
+}
+
+// IsZero reports whether the value is absent, so that the `omitzero` JSON
+// option (Go 1.24+) leaves out absent values but still writes explicit nulls.
+func (o Optional[T]) IsZero() bool {
+	return !o.Present
+}
+
+// SetNull marks the value as present and null.
+func (o *Optional[T]) SetNull() {
+	var zero T
+	o.Value = zero
+	o.Present = true
+}
+
+// ApplyTo copies the value to dst if it is present.
+func (o Optional[T]) ApplyTo(dst *T) {
+	if o.Present {
+		*dst = o.Value
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// An absent value is encoded as null; use `omitzero` to leave it out.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Present {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It is only called for keys present in the input, null included.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Present = true
+	return json.Unmarshal(data, &o.Value)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (o *Optional[T]) UnmarshalText(text []byte) error {
+	u, ok := any(&o.Value).(encoding.TextUnmarshaler)
+	if !ok {
+		return fmt.Errorf("nulled: %T does not implement encoding.TextUnmarshaler", o.Value)
+	}
+	o.Present = true
+	return u.UnmarshalText(text)
+}
+
+func (o *Optional[T]) unmarshalText(text []byte, p Policy) error {
+	u, ok := any(&o.Value).(policyTextUnmarshaler)
+	if !ok {
+		return o.UnmarshalText(text)
+	}
+	o.Present = true
+	return u.unmarshalText(text, p)
+}
+
+func (o *Optional[T]) applyPolicy(p Policy) {
+	if a, ok := any(&o.Value).(policyApplier); ok && o.Present {
+		a.applyPolicy(p)
+	}
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+//
+// yaml.v3 doesn't call unmarshalers for null values, so yaml.Unmarshal
+// leaves a key set to null absent. Decode with the UnmarshalYAML function
+// of this package to have it marked present and null.
+func (o *Optional[T]) UnmarshalYAML(value *yaml.Node) error {
+	o.Present = true
+	return value.Decode(&o.Value)
+}
+
+// optional is implemented by every Optional, whatever its type argument.
+type optional interface {
+	present() bool
+	value() any
+}
+
+func (o Optional[T]) present() bool {
+	return o.Present
+}
+
+func (o Optional[T]) value() any {
+	return o.Value
+}
+
+// Apply copies the present Optional fields of patch onto the fields with the
+// same name in the struct pointed to by dst. The destination field may have
+// the wrapped type or be an Optional itself. Fields of patch that aren't
+// Optional are ignored.
+//
+//	type UserPatch struct {
+//		Name nulled.OptionalString `json:"name"`
+//		Age  nulled.OptionalInt    `json:"age"`
+//	}
+//
+//	var patch UserPatch
+//	_ = json.Unmarshal(body, &patch)
+//	err := nulled.Apply(&user, patch)
+func Apply(dst, patch any) error {
+	dv := reflect.ValueOf(dst)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Struct {
+		return errors.New("nulled: Apply destination must be a non-nil pointer to a struct")
+	}
+	dv = dv.Elem()
+
+	pv := reflect.ValueOf(patch)
+	if pv.Kind() == reflect.Pointer {
+		pv = pv.Elem()
+	}
+	if pv.Kind() != reflect.Struct {
+		return errors.New("nulled: Apply patch must be a struct or a pointer to a struct")
+	}
+
+	pt := pv.Type()
+	for i := 0; i < pt.NumField(); i++ {
+		f := pt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		o, ok := pv.Field(i).Interface().(optional)
+		if !ok || !o.present() {
+			continue
+		}
+		df := dv.FieldByName(f.Name)
+		if !df.IsValid() || !df.CanSet() {
+			return fmt.Errorf("nulled: Apply destination has no field %s", f.Name)
+		}
+		switch v := reflect.ValueOf(o.value()); {
+		case v.Type().AssignableTo(df.Type()):
+			df.Set(v)
+		case f.Type.AssignableTo(df.Type()):
+			df.Set(pv.Field(i))
+		default:
+			return fmt.Errorf("nulled: Apply can't assign field %s of type %s to %s", f.Name, f.Type, df.Type())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package nulled
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type userPatch struct {
+	Name   OptionalString `json:"name" yaml:"name"`
+	Age    OptionalInt    `json:"age" yaml:"age"`
+	Score  OptionalFloat  `json:"score" yaml:"score"`
+	Active OptionalBool   `json:"active" yaml:"active"`
+	Seen   OptionalTime   `json:"seen" yaml:"seen"`
+}
+
+type user struct {
+	Name   String
+	Age    Int
+	Score  Float
+	Active Bool
+	Seen   OptionalTime
+}
+
+func TestOptional_UnmarshalJSON(t *testing.T) {
+	var p userPatch
+	require.NoError(t, json.Unmarshal([]byte(`{"name":null,"age":30,"active":false,"seen":"2023-10-27T10:00:00Z"}`), &p))
+
+	assert.True(t, p.Name.Present)
+	assert.True(t, p.Name.IsNull())
+	assert.False(t, p.Name.Value.Valid)
+
+	assert.Equal(t, OptionalFrom(IntFrom(30)), p.Age)
+	assert.False(t, p.Age.IsNull())
+
+	assert.False(t, p.Score.Present)
+	assert.False(t, p.Score.IsNull())
+
+	assert.Equal(t, OptionalFrom(BoolFrom(false)), p.Active)
+	assert.Equal(t, OptionalFrom(TimeFrom(testTime)), p.Seen)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"age":"x"}`), &p))
+}
+
+func TestOptional_MarshalJSON(t *testing.T) {
+	p := struct {
+		Name OptionalString `json:"name"`
+		Age  OptionalInt    `json:"age"`
+	}{
+		Name: Optional[String]{Present: true},
+		Age:  OptionalFrom(IntFrom(30)),
+	}
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.Equal(t, `{"name":null,"age":30}`, string(data))
+
+	decoded := p
+	decoded.Name.Present = false
+	decoded.Age = OptionalInt{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, p, decoded)
+
+	data, err = json.Marshal(OptionalString{})
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+}
+
+func TestOptional_UnmarshalText(t *testing.T) {
+	var o OptionalInt
+	require.NoError(t, o.UnmarshalText([]byte("")))
+	assert.True(t, o.IsNull())
+
+	require.NoError(t, o.UnmarshalText([]byte("5")))
+	assert.Equal(t, OptionalFrom(IntFrom(5)), o)
+
+	var unsupported Optional[int]
+	assert.Error(t, unsupported.UnmarshalText([]byte("5")))
+	assert.False(t, unsupported.Present)
+}
+
+func TestOptional_UnmarshalYAML(t *testing.T) {
+	var p userPatch
+	require.NoError(t, yaml.Unmarshal([]byte("name: Tom\nage: 30\n"), &p))
+	assert.Equal(t, OptionalFrom(StringFrom("Tom")), p.Name)
+	assert.Equal(t, OptionalFrom(IntFrom(30)), p.Age)
+	assert.False(t, p.Score.Present)
+
+	var n yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("~"), &n))
+	var o OptionalString
+	require.NoError(t, o.UnmarshalYAML(n.Content[0]))
+	assert.True(t, o.IsNull())
+}
+
+func TestUnmarshalYAML(t *testing.T) {
+	var p userPatch
+	require.NoError(t, UnmarshalYAML([]byte("name: null\nage: ~\nscore: 1.5\nactive:\n"), &p))
+	assert.True(t, p.Name.IsNull())
+	assert.True(t, p.Age.IsNull())
+	assert.Equal(t, OptionalFrom(FloatFrom(1.5)), p.Score)
+	assert.True(t, p.Active.IsNull(), "empty value")
+	assert.False(t, p.Seen.Present, "missing key")
+
+	type item struct {
+		Qty OptionalInt
+	}
+	type order struct {
+		userPatch `yaml:",inline"`
+		Items     []item `yaml:"items"`
+		Ship      *struct {
+			Note OptionalString `yaml:"note"`
+		} `yaml:"ship"`
+		Skip OptionalString `yaml:"-"`
+	}
+	var o order
+	data := "name: &n null\nseen: *n\nitems:\n- qty: null\n- qty: 2\nship:\n  note: null\n"
+	require.NoError(t, UnmarshalYAML([]byte(data), &o))
+	assert.True(t, o.Name.IsNull(), "inline")
+	assert.True(t, o.Seen.IsNull(), "alias")
+	assert.True(t, o.Items[0].Qty.IsNull())
+	assert.Equal(t, OptionalFrom(IntFrom(2)), o.Items[1].Qty)
+	assert.True(t, o.Ship.Note.IsNull())
+	assert.False(t, o.Skip.Present)
+
+	require.NoError(t, UnmarshalYAML(nil, &p))
+	assert.Error(t, UnmarshalYAML([]byte("name: [1"), &p))
+}
+
+func TestOptional_ApplyTo(t *testing.T) {
+	name := StringFrom("old")
+	OptionalString{}.ApplyTo(&name)
+	assert.Equal(t, StringFrom("old"), name)
+
+	OptionalFrom(NewString("", false)).ApplyTo(&name)
+	assert.False(t, name.Valid)
+}
+
+func TestApply(t *testing.T) {
+	u := user{
+		Name:   StringFrom("Tom"),
+		Age:    IntFrom(30),
+		Score:  FloatFrom(1.5),
+		Active: BoolFrom(true),
+	}
+	var p userPatch
+	require.NoError(t, json.Unmarshal([]byte(`{"name":null,"age":31,"seen":"2023-10-27T10:00:00Z"}`), &p))
+	require.NoError(t, Apply(&u, &p))
+
+	assert.Equal(t, user{
+		Name:   NewString("", false),
+		Age:    IntFrom(31),
+		Score:  FloatFrom(1.5),
+		Active: BoolFrom(true),
+		Seen:   OptionalFrom(TimeFrom(testTime)),
+	}, u)
+}
+
+func TestApply_Errors(t *testing.T) {
+	var u user
+	assert.Error(t, Apply(u, userPatch{}))
+	assert.Error(t, Apply(&u, 1))
+
+	missing := struct{ Email OptionalString }{Email: OptionalFrom(StringFrom("a@b.c"))}
+	assert.Error(t, Apply(&u, missing))
+
+	mismatch := struct{ Age OptionalString }{Age: OptionalFrom(StringFrom("x"))}
+	assert.Error(t, Apply(&u, mismatch))
+
+	// absent fields are never looked up
+	assert.NoError(t, Apply(&u, struct{ Email OptionalString }{}))
+}
@@ -0,0 +1,297 @@
+package nulled
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyProvider supplies the policy of a Policied value. Implement it on an
+// empty struct to use a policy of your own:
+//
+//	type codePolicy struct{}
+//
+//	func (codePolicy) Policy() nulled.Policy { return nulled.Policy{EmptyAsNull: true} }
+//
+//	type Product struct {
+//		Code nulled.Policied[nulled.String, codePolicy] `json:"code"`
+//	}
+type PolicyProvider interface {
+	Policy() Policy
+}
+
+// Predefined policy providers.
+type (
+	PolicyRaw        struct{} // Policy{}, values are kept as they are
+	PolicyZeroAsNull struct{} // DefaultPolicy with ZeroAsNull
+)
+
+func (PolicyRaw) Policy() Policy { return Policy{} }
+
+func (PolicyZeroAsNull) Policy() Policy {
+	p := DefaultPolicy
+	p.ZeroAsNull = true
+	return p
+}
+
+// policyCodec is implemented by pointers to the nulled types whose encoders
+// and decoders apply DefaultPolicy. Its methods apply p instead.
+type policyCodec interface {
+	policyApplier
+	policyTextUnmarshaler
+	encodeValues(key string, v *url.Values, p Policy) error
+	marshalJSON(p Policy) ([]byte, error)
+	unmarshalJSON(data []byte, p Policy) error
+	marshalText(p Policy) ([]byte, error)
+	scan(value any, p Policy) error
+	value(p Policy) (driver.Value, error)
+}
+
+// policyYAMLCodec is implemented by pointers to the nulled types with YAML
+// methods of their own.
+type policyYAMLCodec interface {
+	marshalYAML(p Policy) (any, error)
+	unmarshalYAML(value *yaml.Node, p Policy) error
+}
+
+// Policied is a T, one of the nulled types, that applies the policy of P
+// instead of DefaultPolicy, the way the `nulled` struct tag does for
+// form.Decode and Normalize:
+//
+//	Stock nulled.Policied[nulled.Int, nulled.PolicyZeroAsNull] `json:"stock"`
+//
+// The policy is applied by the JSON, text, url.Values, SQL, YAML and XML
+// encoders and decoders in place of DefaultPolicy, so a policy that keeps
+// more than DefaultPolicy keeps it in every encoding. Binary and gob
+// encodings are exact, like those of T. Normalize applies the policy of P
+// whatever the tag of the field. Types that have no null policy, such as
+// Bytes, behave like T.
+type Policied[T any, P PolicyProvider] struct {
+	V T
+}
+
+// PoliciedFrom creates a Policied holding v under the policy of P:
+//
+//	stock := nulled.PoliciedFrom[nulled.PolicyZeroAsNull](nulled.IntFrom(0))
+func PoliciedFrom[P PolicyProvider, T any](v T) Policied[T, P] {
+	w := Policied[T, P]{V: v}
+	w.applyPolicy(w.policy())
+	return w
+}
+
+func (v Policied[T, P]) policy() Policy {
+	var p P
+	return p.Policy()
+}
+
+// codec returns the policy aware methods of the value, if T has them.
+func (v *Policied[T, P]) codec() (policyCodec, bool) {
+	c, ok := any(&v.V).(policyCodec)
+	return c, ok
+}
+
+// applyPolicy applies the policy of P. It ignores p, so that Normalize
+// leaves the policy of P in place.
+func (v *Policied[T, P]) applyPolicy(Policy) {
+	if a, ok := any(&v.V).(policyApplier); ok {
+		a.applyPolicy(v.policy())
+	}
+}
+
+// IsZero reports whether the value is null under the policy of P.
+func (v Policied[T, P]) IsZero() bool {
+	v.applyPolicy(v.policy())
+	z, ok := any(v.V).(interface{ IsZero() bool })
+	return ok && z.IsZero()
+}
+
+func (v Policied[T, P]) EncodeValues(key string, values *url.Values) error {
+	if c, ok := v.codec(); ok {
+		return c.encodeValues(key, values, v.policy())
+	}
+	e, ok := any(v.V).(interface {
+		EncodeValues(key string, v *url.Values) error
+	})
+	if !ok {
+		return fmt.Errorf("nulled: %T does not implement query.Encoder", v.V)
+	}
+	return e.EncodeValues(key, values)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v Policied[T, P]) MarshalJSON() ([]byte, error) {
+	if c, ok := v.codec(); ok {
+		return c.marshalJSON(v.policy())
+	}
+	return json.Marshal(v.V)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *Policied[T, P]) UnmarshalJSON(data []byte) error {
+	if c, ok := v.codec(); ok {
+		return c.unmarshalJSON(data, v.policy())
+	}
+	return json.Unmarshal(data, &v.V)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (v Policied[T, P]) MarshalText() ([]byte, error) {
+	if c, ok := v.codec(); ok {
+		return c.marshalText(v.policy())
+	}
+	m, ok := any(v.V).(encoding.TextMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("nulled: %T does not implement encoding.TextMarshaler", v.V)
+	}
+	return m.MarshalText()
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (v *Policied[T, P]) UnmarshalText(text []byte) error {
+	u, ok := any(&v.V).(encoding.TextUnmarshaler)
+	if !ok {
+		return fmt.Errorf("nulled: %T does not implement encoding.TextUnmarshaler", v.V)
+	}
+	return UnmarshalTextWithPolicy(u, text, v.policy())
+}
+
+// Scan implements the sql.Scanner interface.
+func (v *Policied[T, P]) Scan(value any) error {
+	if c, ok := v.codec(); ok {
+		return c.scan(value, v.policy())
+	}
+	s, ok := any(&v.V).(sql.Scanner)
+	if !ok {
+		return &ScanError{Src: value, Type: fmt.Sprintf("%T", v.V), Err: ErrUnsupportedType}
+	}
+	return s.Scan(value)
+}
+
+// Value implements the driver.Valuer interface.
+func (v Policied[T, P]) Value() (driver.Value, error) {
+	if c, ok := v.codec(); ok {
+		return c.value(v.policy())
+	}
+	dv, ok := any(v.V).(driver.Valuer)
+	if !ok {
+		return nil, fmt.Errorf("nulled: %T does not implement driver.Valuer", v.V)
+	}
+	return dv.Value()
+}
+
+// MarshalYAML implements the yaml.Marshaler interface. Types without YAML
+// methods of their own are written as text, as yaml.v3 does for them.
+func (v Policied[T, P]) MarshalYAML() (any, error) {
+	if c, ok := any(&v.V).(policyYAMLCodec); ok {
+		return c.marshalYAML(v.policy())
+	}
+	if v.IsZero() {
+		return nil, nil
+	}
+	text, err := v.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(text), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface. Scalars of types
+// without YAML methods of their own are read as text.
+func (v *Policied[T, P]) UnmarshalYAML(value *yaml.Node) error {
+	if c, ok := any(&v.V).(policyYAMLCodec); ok {
+		return c.unmarshalYAML(value, v.policy())
+	}
+	if isYAMLNull(value) {
+		var zero T
+		v.V = zero
+		return nil
+	}
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("yaml: line %d: cannot unmarshal %s into %T", value.Line, value.ShortTag(), v.V)
+	}
+	return v.UnmarshalText([]byte(value.Value))
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+// The value is written in its text form.
+func (v Policied[T, P]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if v.IsZero() {
+		return marshalXMLElement(e, start, "", false)
+	}
+	text, err := v.MarshalText()
+	if err != nil {
+		return err
+	}
+	return marshalXMLElement(e, start, string(text), true)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (v *Policied[T, P]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		var zero T
+		v.V = zero
+		return nil
+	}
+	return v.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (v Policied[T, P]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if v.IsZero() {
+		return xml.Attr{}, nil
+	}
+	text, err := v.MarshalText()
+	if err != nil {
+		return xml.Attr{}, err
+	}
+	return xml.Attr{Name: name, Value: string(text)}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (v *Policied[T, P]) UnmarshalXMLAttr(attr xml.Attr) error {
+	return v.UnmarshalText([]byte(attr.Value))
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (v Policied[T, P]) MarshalBinary() ([]byte, error) {
+	m, ok := any(v.V).(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("nulled: %T does not implement encoding.BinaryMarshaler", v.V)
+	}
+	return m.MarshalBinary()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (v *Policied[T, P]) UnmarshalBinary(data []byte) error {
+	u, ok := any(&v.V).(encoding.BinaryUnmarshaler)
+	if !ok {
+		return fmt.Errorf("nulled: %T does not implement encoding.BinaryUnmarshaler", v.V)
+	}
+	return u.UnmarshalBinary(data)
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (v Policied[T, P]) GobEncode() ([]byte, error) {
+	return v.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+// It also reads the format written by earlier versions of T.GobEncode.
+func (v *Policied[T, P]) GobDecode(data []byte) error {
+	if g, ok := any(&v.V).(interface{ GobDecode([]byte) error }); ok {
+		return g.GobDecode(data)
+	}
+	return v.UnmarshalBinary(data)
+}
@@ -0,0 +1,190 @@
+package nulled
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type rawString = Policied[String, PolicyRaw]
+
+func TestPolicied_JSON(t *testing.T) {
+	type product struct {
+		Code  rawString                           `json:"code"`
+		Stock Policied[Int, PolicyZeroAsNull]     `json:"stock"`
+		Price Policied[Decimal, PolicyZeroAsNull] `json:"price"`
+		Name  String                              `json:"name"`
+	}
+
+	var p product
+	require.NoError(t, json.Unmarshal([]byte(`{"code": " a ", "stock": 0, "price": "0.00", "name": " b "}`), &p))
+	assert.Equal(t, NewString(" a ", true), p.Code.V)
+	assert.False(t, p.Stock.V.Valid)
+	assert.False(t, p.Price.V.Valid)
+	assert.Equal(t, StringFrom("b"), p.Name)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"code": ""}`), &p))
+	assert.Equal(t, NewString("", true), p.Code.V, "empty is kept")
+
+	p = product{
+		Code:  rawString{V: NewString(" a ", true)},
+		Stock: PoliciedFrom[PolicyZeroAsNull](NewInt(0, true)),
+	}
+	data, err := json.Marshal(p)
+	require.NoError(t, err)
+	assert.Equal(t, `{"code":" a ","stock":null,"price":null,"name":null}`, string(data))
+}
+
+func TestPolicied_OverridesDefault(t *testing.T) {
+	withPolicy(t, Policy{Trim: true, EmptyAsNull: true, WhitespaceAsNull: true, ZeroAsNull: true})
+
+	var i Policied[Int, PolicyRaw]
+	require.NoError(t, json.Unmarshal([]byte(`0`), &i))
+	assert.Equal(t, NewInt(0, true), i.V)
+	require.NoError(t, i.UnmarshalText([]byte("0")))
+	assert.Equal(t, NewInt(0, true), i.V)
+	require.NoError(t, i.Scan(int64(0)))
+	assert.Equal(t, NewInt(0, true), i.V)
+
+	v, err := i.Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), v)
+	text, err := i.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "0", string(text))
+	values := url.Values{}
+	require.NoError(t, i.EncodeValues("i", &values))
+	assert.Equal(t, "0", values.Get("i"))
+
+	var d Policied[Duration, PolicyRaw]
+	require.NoError(t, d.Scan(int64(0)))
+	assert.Equal(t, NewDuration(0, true), d.V)
+	var b Policied[BigInt, PolicyRaw]
+	require.NoError(t, b.Scan("0"))
+	assert.True(t, b.V.Valid)
+}
+
+func TestPolicied_YAML(t *testing.T) {
+	type config struct {
+		Code  rawString                           `yaml:"code"`
+		Price Policied[Decimal, PolicyZeroAsNull] `yaml:"price"`
+		Count Policied[Int, PolicyZeroAsNull]     `yaml:"count,omitempty"`
+	}
+
+	var c config
+	require.NoError(t, yaml.Unmarshal([]byte("code: \" a \"\nprice: 0.00\ncount: 0\n"), &c))
+	assert.Equal(t, NewString(" a ", true), c.Code.V)
+	assert.False(t, c.Price.V.Valid)
+	assert.False(t, c.Count.V.Valid)
+
+	c.Price.V = DecimalFrom(1990, 2)
+	c.Count.V = NewInt(0, true)
+	data, err := yaml.Marshal(c)
+	require.NoError(t, err)
+	assert.Equal(t, "code: ' a '\nprice: 19.90\n", string(data))
+}
+
+func TestPolicied_XML(t *testing.T) {
+	type item struct {
+		XMLName xml.Name                        `xml:"item"`
+		ID      Policied[Int, PolicyZeroAsNull] `xml:"id,attr"`
+		Code    rawString                       `xml:"code"`
+		Stock   Policied[Int, PolicyZeroAsNull] `xml:"stock"`
+	}
+
+	data, err := xml.Marshal(item{ID: Policied[Int, PolicyZeroAsNull]{V: NewInt(0, true)}, Code: rawString{V: NewString(" a ", true)}})
+	require.NoError(t, err)
+	assert.Equal(t, `<item><code> a </code><stock xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></stock></item>`, string(data))
+
+	var it item
+	require.NoError(t, xml.Unmarshal([]byte(`<item id="0"><code> b </code><stock>0</stock></item>`), &it))
+	assert.False(t, it.ID.V.Valid)
+	assert.Equal(t, NewString(" b ", true), it.Code.V)
+	assert.False(t, it.Stock.V.Valid)
+}
+
+func TestPolicied_Normalize(t *testing.T) {
+	v := struct {
+		Stock Policied[Int, PolicyZeroAsNull] `nulled:""`
+		Code  rawString
+	}{
+		Stock: Policied[Int, PolicyZeroAsNull]{V: NewInt(0, true)},
+		Code:  rawString{V: NewString(" ", true)},
+	}
+	require.NoError(t, Normalize(&v))
+	assert.False(t, v.Stock.V.Valid, "the policy of P wins over the tag")
+	assert.Equal(t, NewString(" ", true), v.Code.V)
+
+	var s rawString
+	require.NoError(t, UnmarshalTextWithPolicy(&s, []byte(" "), DefaultPolicy))
+	assert.Equal(t, NewString(" ", true), s.V)
+}
+
+func TestPolicied_Types(t *testing.T) {
+	var strict Policied[StrictString, PolicyZeroAsNull]
+	require.NoError(t, json.Unmarshal([]byte(`" "`), &strict))
+	assert.Equal(t, NewString(" ", true), strict.V.String, "Strict types keep their JSON")
+
+	var lenient Policied[LenientInt, PolicyZeroAsNull]
+	require.NoError(t, json.Unmarshal([]byte(`"0"`), &lenient))
+	assert.False(t, lenient.V.Valid)
+	require.NoError(t, json.Unmarshal([]byte(`"12.00"`), &lenient))
+	assert.Equal(t, int64(12), lenient.V.Int64)
+
+	day := Policied[TimeLayout[LayoutDateOnly], PolicyRaw]{}
+	require.NoError(t, json.Unmarshal([]byte(`"2023-10-27"`), &day))
+	data, err := json.Marshal(day)
+	require.NoError(t, err)
+	assert.Equal(t, `"2023-10-27"`, string(data))
+
+	var ti Policied[Time, PolicyRaw]
+	require.NoError(t, json.Unmarshal([]byte(`"2023-10-27T10:00:00Z"`), &ti))
+	assert.True(t, testTime.Equal(ti.V.Time))
+	require.NoError(t, json.Unmarshal([]byte(`""`), &ti))
+	assert.False(t, ti.V.Valid)
+	assert.Error(t, json.Unmarshal([]byte(`"soon"`), &ti))
+
+	var unix Policied[UnixTime, PolicyRaw]
+	require.NoError(t, json.Unmarshal([]byte(`1698400800`), &unix))
+	assert.Equal(t, int64(1698400800), unix.V.Int64())
+
+	var raw Policied[Bytes, PolicyZeroAsNull]
+	require.NoError(t, json.Unmarshal([]byte(`""`), &raw))
+	assert.True(t, raw.V.Valid, "Bytes has no policy")
+	data, err = json.Marshal(raw)
+	require.NoError(t, err)
+	assert.Equal(t, `""`, string(data))
+
+	var n Policied[Int32, PolicyZeroAsNull]
+	require.NoError(t, json.Unmarshal([]byte(`0`), &n))
+	assert.False(t, n.V.Valid)
+
+	var d Policied[Duration, PolicyZeroAsNull]
+	require.NoError(t, d.UnmarshalText([]byte("0s")))
+	assert.False(t, d.V.Valid)
+	require.NoError(t, d.UnmarshalText([]byte("1m")))
+	assert.Equal(t, DurationFrom(time.Minute), d.V)
+}
+
+func TestPolicied_Binary(t *testing.T) {
+	in := Policied[Int, PolicyZeroAsNull]{V: NewInt(0, true)}
+	data, err := in.MarshalBinary()
+	require.NoError(t, err)
+
+	var out Policied[Int, PolicyZeroAsNull]
+	require.NoError(t, out.UnmarshalBinary(data))
+	assert.Equal(t, in, out, "binary is exact")
+	assert.True(t, out.IsZero())
+	assert.False(t, Policied[Int, PolicyRaw]{V: NewInt(0, true)}.IsZero())
+
+	// gob data written before the field became Policied
+	var legacy Policied[Int, PolicyZeroAsNull]
+	require.NoError(t, legacy.GobDecode(legacyGobEncode(int64(42), true)))
+	assert.Equal(t, IntFrom(42), legacy.V)
+}
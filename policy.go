@@ -0,0 +1,151 @@
+package nulled
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Policy describes which values are considered null and how strings are
+// cleaned up. It is honored by the From and FromPtr constructors and by
+// every encoder and decoder except the binary and gob encodings, which
+// reproduce values exactly. NewString, NewInt and friends don't apply it, so
+// they can still build any value explicitly.
+type Policy struct {
+	// Trim removes leading and trailing white space from strings, and from
+	// text before it is parsed as a number.
+	Trim bool
+	// EmptyAsNull treats the empty string as null.
+	EmptyAsNull bool
+	// WhitespaceAsNull treats strings made of white space only as null.
+	WhitespaceAsNull bool
+	// ZeroAsNull treats zero numbers as null: Int, Float, the sized numbers,
+	// Decimal, BigInt and Duration. The zero time is always null, whatever
+	// the policy.
+	ZeroAsNull bool
+}
+
+// DefaultPolicy is the package-wide policy. It trims strings and treats
+// empty and blank strings as null, while 0 stays a valid number.
+var DefaultPolicy = Policy{Trim: true, EmptyAsNull: true, WhitespaceAsNull: true}
+
+// ParsePolicy parses the `nulled` struct tag, a comma separated list of the
+// options to enable: trim, empty, whitespace and zero. An empty tag enables
+// none of them.
+//
+//	Code  nulled.String `nulled:"empty"`      // keeps white space
+//	Stock nulled.Int    `nulled:"zero"`       // 0 is null
+//	Note  nulled.String `nulled:""`           // keeps everything
+//
+// The tag is read by form.Decode and Normalize only. The JSON, text, SQL,
+// YAML and XML codecs apply DefaultPolicy whatever the tag, so use Policied
+// for a field policy that every codec honors.
+func ParsePolicy(tag string) (Policy, error) {
+	var p Policy
+	for _, opt := range strings.Split(tag, ",") {
+		switch strings.TrimSpace(opt) {
+		case "":
+		case "trim":
+			p.Trim = true
+		case "empty":
+			p.EmptyAsNull = true
+		case "whitespace":
+			p.WhitespaceAsNull = true
+		case "zero":
+			p.ZeroAsNull = true
+		default:
+			return Policy{}, fmt.Errorf("nulled: unknown policy option %q", opt)
+		}
+	}
+	return p, nil
+}
+
+// FieldPolicy returns the policy of a struct field: the one in its `nulled`
+// tag, or DefaultPolicy when the field has none.
+func FieldPolicy(f reflect.StructField) (Policy, error) {
+	tag, ok := f.Tag.Lookup("nulled")
+	if !ok {
+		return DefaultPolicy, nil
+	}
+	return ParsePolicy(tag)
+}
+
+// text applies the Trim option to text that is about to be parsed.
+func (p Policy) text(s string) string {
+	if p.Trim {
+		return strings.TrimSpace(s)
+	}
+	return s
+}
+
+// policyApplier is implemented by pointers to the nulled types.
+type policyApplier interface {
+	applyPolicy(p Policy)
+}
+
+// policyTextUnmarshaler is implemented by pointers to the nulled types.
+type policyTextUnmarshaler interface {
+	unmarshalText(text []byte, p Policy) error
+}
+
+// UnmarshalTextWithPolicy decodes text into v like v.UnmarshalText, but
+// applies p instead of DefaultPolicy. Types from other packages are decoded
+// with their own UnmarshalText.
+func UnmarshalTextWithPolicy(v encoding.TextUnmarshaler, text []byte, p Policy) error {
+	if u, ok := v.(policyTextUnmarshaler); ok {
+		return u.unmarshalText(text, p)
+	}
+	return v.UnmarshalText(text)
+}
+
+// Normalize applies the `nulled` tag policy of every field of the struct
+// pointed to by v, recursing into nested structs. Use it after decoding JSON,
+// YAML, XML or database rows to apply per field policies:
+//
+//	var req struct {
+//		Stock nulled.Int `json:"stock" nulled:"zero"`
+//	}
+//	_ = json.Unmarshal(body, &req)
+//	err := nulled.Normalize(&req)
+//
+// Decoders apply DefaultPolicy first, so a field policy can only null
+// values that DefaultPolicy kept; it can't bring back a value that
+// DefaultPolicy already turned into null. Policied fields are decoded under
+// their own policy instead, which Normalize applies whatever their tag.
+func Normalize(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("nulled: Normalize needs a non-nil pointer to a struct")
+	}
+	return normalizeStruct(rv.Elem())
+}
+
+func normalizeStruct(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		fv := v.Field(i)
+		if !fv.CanAddr() || !fv.Addr().CanInterface() {
+			continue
+		}
+		if a, ok := fv.Addr().Interface().(policyApplier); ok {
+			p, err := FieldPolicy(f)
+			if err != nil {
+				return fmt.Errorf("nulled: field %s: %w", f.Name, err)
+			}
+			a.applyPolicy(p)
+			continue
+		}
+		if fv.Kind() == reflect.Struct {
+			if err := normalizeStruct(fv); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
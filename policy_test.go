@@ -0,0 +1,225 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withPolicy(t *testing.T, p Policy) {
+	t.Helper()
+	old := DefaultPolicy
+	DefaultPolicy = p
+	t.Cleanup(func() { DefaultPolicy = old })
+}
+
+func TestPolicy_String(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy Policy
+		in     String
+		want   String
+	}{
+		{name: "default trims", policy: DefaultPolicy, in: NewString(" a ", true), want: StringFrom("a")},
+		{name: "default empty", policy: DefaultPolicy, in: NewString("", true), want: NewString("", false)},
+		{name: "default blank", policy: DefaultPolicy, in: NewString(" \t", true), want: NewString("", false)},
+		{name: "null", policy: Policy{}, in: NewString("x", false), want: NewString("", false)},
+		{name: "raw keeps everything", policy: Policy{}, in: NewString(" ", true), want: NewString(" ", true)},
+		{name: "empty only", policy: Policy{EmptyAsNull: true}, in: NewString(" ", true), want: NewString(" ", true)},
+		{name: "whitespace only", policy: Policy{WhitespaceAsNull: true}, in: NewString(" ", true), want: NewString("", false)},
+		{name: "trim keeps empty", policy: Policy{Trim: true}, in: NewString(" ", true), want: NewString("", true)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.in.WithPolicy(tt.policy))
+		})
+	}
+}
+
+func TestPolicy_Numbers(t *testing.T) {
+	assert.Equal(t, IntFrom(0), IntFrom(0).WithPolicy(DefaultPolicy))
+	assert.Equal(t, NewInt(0, false), NewInt(0, true).WithPolicy(Policy{ZeroAsNull: true}))
+	assert.Equal(t, IntFrom(1), IntFrom(1).WithPolicy(Policy{ZeroAsNull: true}))
+	assert.Equal(t, NewFloat(0, false), NewFloat(0, true).WithPolicy(Policy{ZeroAsNull: true}))
+	assert.Equal(t, BoolFrom(false), BoolFrom(false).WithPolicy(Policy{ZeroAsNull: true}))
+	assert.Equal(t, TimeFrom(testTime), NewTime(testTime, true).WithPolicy(Policy{ZeroAsNull: true}))
+	assert.Equal(t, NewTime(time.Time{}, false), NewTime(time.Time{}, true).WithPolicy(Policy{}))
+}
+
+func TestPolicy_DefaultAppliesEverywhere(t *testing.T) {
+	withPolicy(t, Policy{Trim: true, EmptyAsNull: true, WhitespaceAsNull: true, ZeroAsNull: true})
+
+	assert.False(t, IntFrom(0).Valid)
+	assert.False(t, FloatFrom(0).Valid)
+
+	var i Int
+	require.NoError(t, json.Unmarshal([]byte(`0`), &i))
+	assert.False(t, i.Valid)
+	require.NoError(t, i.UnmarshalText([]byte(" 0 ")))
+	assert.False(t, i.Valid)
+	require.NoError(t, i.Scan(int64(0)))
+	assert.False(t, i.Valid)
+
+	var f Float
+	require.NoError(t, json.Unmarshal([]byte(`0.0`), &f))
+	assert.False(t, f.Valid)
+
+	data, err := json.Marshal(NewInt(0, true))
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+
+	v, err := NewFloat(0, true).Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+
+	values := url.Values{}
+	require.NoError(t, NewInt(0, true).EncodeValues("i", &values))
+	assert.False(t, values.Has("i"))
+
+	// binary encodings are exact
+	data, err = NewInt(0, true).MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, i.UnmarshalBinary(data))
+	assert.Equal(t, NewInt(0, true), i)
+}
+
+func TestPolicy_ZeroNumbers(t *testing.T) {
+	zero := Policy{ZeroAsNull: true}
+	assert.False(t, DecimalFrom(0, 2).WithPolicy(zero).Valid)
+	assert.True(t, DecimalFrom(1, 2).WithPolicy(zero).Valid)
+	assert.False(t, DurationFrom(0).WithPolicy(zero).Valid)
+	assert.True(t, DurationFrom(time.Second).WithPolicy(zero).Valid)
+
+	var d Decimal
+	require.NoError(t, UnmarshalTextWithPolicy(&d, []byte("0.00"), zero))
+	assert.False(t, d.Valid)
+	var dur Duration
+	require.NoError(t, UnmarshalTextWithPolicy(&dur, []byte("0s"), zero))
+	assert.False(t, dur.Valid)
+
+	v := struct {
+		Price   Decimal  `nulled:"zero"`
+		Timeout Duration `nulled:"zero"`
+		Amount  BigInt   `nulled:"zero"`
+		Count   Int32    `nulled:"zero"`
+	}{DecimalFrom(0, 2), DurationFrom(0), BigIntFromInt64(0), Int32From(0)}
+	require.NoError(t, Normalize(&v))
+	assert.False(t, v.Price.Valid)
+	assert.False(t, v.Timeout.Valid)
+	assert.False(t, v.Amount.Valid)
+	assert.False(t, v.Count.Valid)
+
+	withPolicy(t, Policy{ZeroAsNull: true})
+	assert.False(t, DecimalFrom(0, 0).Valid)
+	assert.False(t, DurationFrom(0).Valid)
+	require.NoError(t, json.Unmarshal([]byte(`0.0`), &d))
+	assert.False(t, d.Valid)
+	require.NoError(t, dur.Scan(int64(0)))
+	assert.False(t, dur.Valid)
+	data, err := json.Marshal(NewDuration(0, true))
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+}
+
+func TestPolicy_NoTrim(t *testing.T) {
+	withPolicy(t, Policy{EmptyAsNull: true})
+
+	assert.Equal(t, NewString(" a ", true), StringFrom(" a "))
+	assert.Equal(t, NewString(" ", true), StringFrom(" "))
+
+	var s String
+	require.NoError(t, json.Unmarshal([]byte(`" a "`), &s))
+	assert.Equal(t, NewString(" a ", true), s)
+	require.NoError(t, s.Scan(" a "))
+	assert.Equal(t, NewString(" a ", true), s)
+
+	v, err := NewString(" ", true).Value()
+	require.NoError(t, err)
+	assert.Equal(t, driver.Value(" "), v)
+}
+
+func TestPolicy_StringCodecsTrim(t *testing.T) {
+	var s String
+	require.NoError(t, json.Unmarshal([]byte(`" a "`), &s))
+	assert.Equal(t, StringFrom("a"), s)
+	require.NoError(t, s.UnmarshalText([]byte(" ")))
+	assert.False(t, s.Valid)
+
+	data, err := json.Marshal(NewString("  ", true))
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+
+	values := url.Values{}
+	require.NoError(t, NewString(" a ", true).EncodeValues("s", &values))
+	assert.Equal(t, "a", values.Get("s"))
+}
+
+func TestParsePolicy(t *testing.T) {
+	p, err := ParsePolicy("trim, empty,whitespace,zero")
+	require.NoError(t, err)
+	assert.Equal(t, Policy{Trim: true, EmptyAsNull: true, WhitespaceAsNull: true, ZeroAsNull: true}, p)
+
+	p, err = ParsePolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, Policy{}, p)
+
+	_, err = ParsePolicy("trim,bogus")
+	assert.Error(t, err)
+}
+
+func TestUnmarshalTextWithPolicy(t *testing.T) {
+	var s String
+	require.NoError(t, UnmarshalTextWithPolicy(&s, []byte(""), Policy{}))
+	assert.Equal(t, NewString("", true), s)
+
+	var i Int
+	require.NoError(t, UnmarshalTextWithPolicy(&i, []byte("0"), Policy{ZeroAsNull: true}))
+	assert.False(t, i.Valid)
+
+	var o OptionalString
+	require.NoError(t, UnmarshalTextWithPolicy(&o, []byte(" a "), Policy{}))
+	assert.Equal(t, OptionalFrom(NewString(" a ", true)), o)
+}
+
+func TestNormalize(t *testing.T) {
+	type inner struct {
+		Count Int `nulled:"zero"`
+	}
+	v := struct {
+		Stock   Int    `nulled:"zero"`
+		Price   Float  `nulled:"zero"`
+		Name    String `nulled:"whitespace"`
+		Plain   Int
+		Inner   inner
+		Patch   OptionalInt `nulled:"zero"`
+		ignored Int         `nulled:"zero"`
+	}{
+		Stock:   IntFrom(0),
+		Price:   FloatFrom(0),
+		Name:    NewString(" ", true),
+		Plain:   IntFrom(0),
+		Inner:   inner{Count: IntFrom(0)},
+		Patch:   OptionalFrom(IntFrom(0)),
+		ignored: IntFrom(0),
+	}
+	require.NoError(t, Normalize(&v))
+	assert.False(t, v.Stock.Valid)
+	assert.False(t, v.Price.Valid)
+	assert.False(t, v.Name.Valid)
+	assert.True(t, v.Plain.Valid)
+	assert.False(t, v.Inner.Count.Valid)
+	assert.True(t, v.Patch.IsNull())
+	assert.True(t, v.ignored.Valid)
+
+	bad := struct {
+		I Int `nulled:"bogus"`
+	}{}
+	assert.Error(t, Normalize(&bad))
+	assert.Error(t, Normalize(bad))
+}
@@ -0,0 +1,236 @@
+package nulled
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedType is reported (wrapped in a ScanError) when a source value
+// has a type that can't be converted to the destination type at all.
+var ErrUnsupportedType = errors.New("unsupported type")
+
+// ScanError reports a source value, from a database driver or from text,
+// that can't be converted to a nulled type.
+// Err is strconv.ErrRange for out of range values, strconv.ErrSyntax for
+// unparsable text and ErrUnsupportedType for unknown source types, so callers
+// can use errors.Is to tell them apart.
+type ScanError struct {
+	Src  any    // value handed over by the driver or decoder
+	Type string // destination type, e.g. "Int"
+	Err  error
+}
+
+func (e *ScanError) Error() string {
+	return fmt.Sprintf("nulled: converting %T (%v) to %s: %v", e.Src, e.Src, e.Type, e.Err)
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// toText returns the source as text if it is a string or []byte.
+func toText(src any) (string, bool) {
+	switch v := src.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	}
+	return "", false
+}
+
+// toInt64 converts src to an int64. It accepts every integer width, integral
+// floats and decimal text such as "12" or "12.00". The returned bool is false
+// when src is nil or blank text.
+func toInt64(src any) (int64, bool, error) {
+	fail := func(err error) (int64, bool, error) {
+		return 0, false, &ScanError{Src: src, Type: "Int", Err: err}
+	}
+	switch v := src.(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		return v, true, nil
+	case int:
+		return int64(v), true, nil
+	case int8:
+		return int64(v), true, nil
+	case int16:
+		return int64(v), true, nil
+	case int32:
+		return int64(v), true, nil
+	case uint:
+		if uint64(v) > math.MaxInt64 {
+			return fail(strconv.ErrRange)
+		}
+		return int64(v), true, nil
+	case uint8:
+		return int64(v), true, nil
+	case uint16:
+		return int64(v), true, nil
+	case uint32:
+		return int64(v), true, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return fail(strconv.ErrRange)
+		}
+		return int64(v), true, nil
+	case float32:
+		return floatToInt64(src, float64(v))
+	case float64:
+		return floatToInt64(src, v)
+	case bool:
+		if v {
+			return 1, true, nil
+		}
+		return 0, true, nil
+	}
+
+	s, ok := toText(src)
+	if !ok {
+		return fail(ErrUnsupportedType)
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false, nil
+	}
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err == nil {
+		return i, true, nil
+	}
+	if errors.Is(err, strconv.ErrRange) {
+		return fail(strconv.ErrRange)
+	}
+	// DECIMAL columns come back as "12.00"
+	f, ferr := strconv.ParseFloat(s, 64)
+	if ferr != nil {
+		return fail(strconv.ErrSyntax)
+	}
+	return floatToInt64(src, f)
+}
+
+func floatToInt64(src any, f float64) (int64, bool, error) {
+	if math.IsNaN(f) || math.Trunc(f) != f {
+		return 0, false, &ScanError{Src: src, Type: "Int", Err: strconv.ErrSyntax}
+	}
+	// float64(math.MaxInt64) rounds up to 2^63, which is already out of range
+	if f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false, &ScanError{Src: src, Type: "Int", Err: strconv.ErrRange}
+	}
+	return int64(f), true, nil
+}
+
+// widenFloat32 converts f to a float64 through its shortest decimal form, so
+// 1.1 stays 1.1 instead of becoming 1.100000023841858.
+func widenFloat32(f float32) float64 {
+	w, _ := strconv.ParseFloat(strconv.FormatFloat(float64(f), 'g', -1, 32), 64)
+	return w
+}
+
+// toFloat64 converts src to a float64. It accepts every integer width,
+// float32, float64 and numeric text. The returned bool is false when src is
+// nil or blank text.
+func toFloat64(src any) (float64, bool, error) {
+	fail := func(err error) (float64, bool, error) {
+		return 0, false, &ScanError{Src: src, Type: "Float", Err: err}
+	}
+	switch v := src.(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		return v, true, nil
+	case float32:
+		return widenFloat32(v), true, nil
+	case int64:
+		return float64(v), true, nil
+	case int:
+		return float64(v), true, nil
+	case int8:
+		return float64(v), true, nil
+	case int16:
+		return float64(v), true, nil
+	case int32:
+		return float64(v), true, nil
+	case uint:
+		return float64(v), true, nil
+	case uint8:
+		return float64(v), true, nil
+	case uint16:
+		return float64(v), true, nil
+	case uint32:
+		return float64(v), true, nil
+	case uint64:
+		return float64(v), true, nil
+	}
+
+	s, ok := toText(src)
+	if !ok {
+		return fail(ErrUnsupportedType)
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, false, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return fail(strconv.ErrRange)
+		}
+		return fail(strconv.ErrSyntax)
+	}
+	return f, true, nil
+}
+
+// parseBool parses the boolean spellings used by databases and loosely typed
+// APIs: 1/0, t/f, true/false, y/n, yes/no and on/off, ignoring case.
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, nil
+	case "0", "f", "false", "n", "no", "off":
+		return false, nil
+	}
+	return false, strconv.ErrSyntax
+}
+
+// toBool converts src to a bool. It accepts bool, the integers 0 and 1, and
+// the text spellings understood by parseBool. The returned bool is false
+// when src is nil or blank text.
+func toBool(src any) (bool, bool, error) {
+	fail := func(err error) (bool, bool, error) {
+		return false, false, &ScanError{Src: src, Type: "Bool", Err: err}
+	}
+	switch v := src.(type) {
+	case nil:
+		return false, false, nil
+	case bool:
+		return v, true, nil
+	}
+
+	if s, ok := toText(src); ok {
+		if strings.TrimSpace(s) == "" {
+			return false, false, nil
+		}
+		b, err := parseBool(s)
+		if err != nil {
+			return fail(err)
+		}
+		return b, true, nil
+	}
+
+	// TINYINT(1) and friends
+	f, _, err := toFloat64(src)
+	if err != nil {
+		return fail(ErrUnsupportedType)
+	}
+	switch f {
+	case 0:
+		return false, true, nil
+	case 1:
+		return true, true, nil
+	}
+	return fail(strconv.ErrRange)
+}
@@ -0,0 +1,124 @@
+package nulled
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInt_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Int
+		wantErr error
+	}{
+		{name: "nil", src: nil, want: NewInt(0, false)},
+		{name: "int64", src: int64(42), want: IntFrom(42)},
+		{name: "int8", src: int8(-8), want: IntFrom(-8)},
+		{name: "uint32", src: uint32(32), want: IntFrom(32)},
+		{name: "uint64", src: uint64(64), want: IntFrom(64)},
+		{name: "uint64 overflow", src: uint64(math.MaxUint64), wantErr: strconv.ErrRange},
+		{name: "float64", src: float64(12), want: IntFrom(12)},
+		{name: "fractional float64", src: 12.5, wantErr: strconv.ErrSyntax},
+		{name: "huge float64", src: 1e20, wantErr: strconv.ErrRange},
+		{name: "bytes", src: []byte("9007199254740993"), want: IntFrom(9007199254740993)},
+		{name: "decimal bytes", src: []byte("12.00"), want: IntFrom(12)},
+		{name: "string", src: " -7 ", want: IntFrom(-7)},
+		{name: "blank string", src: " ", want: NewInt(0, false)},
+		{name: "string overflow", src: "99999999999999999999", wantErr: strconv.ErrRange},
+		{name: "invalid string", src: "abc", wantErr: strconv.ErrSyntax},
+		{name: "unsupported", src: struct{}{}, wantErr: ErrUnsupportedType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var i Int
+			err := i.Scan(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				var scanErr *ScanError
+				assert.True(t, errors.As(err, &scanErr))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, i)
+		})
+	}
+}
+
+func TestFloat_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Float
+		wantErr error
+	}{
+		{name: "nil", src: nil, want: NewFloat(0, false)},
+		{name: "float64", src: 1.25, want: FloatFrom(1.25)},
+		{name: "float32", src: float32(1.1), want: FloatFrom(1.1)},
+		{name: "int64", src: int64(3), want: FloatFrom(3)},
+		{name: "uint16", src: uint16(3), want: FloatFrom(3)},
+		{name: "decimal bytes", src: []byte("19.99"), want: FloatFrom(19.99)},
+		{name: "string", src: "1e3", want: FloatFrom(1000)},
+		{name: "blank bytes", src: []byte(""), want: NewFloat(0, false)},
+		{name: "string overflow", src: "1e400", wantErr: strconv.ErrRange},
+		{name: "invalid string", src: "abc", wantErr: strconv.ErrSyntax},
+		{name: "unsupported", src: true, wantErr: ErrUnsupportedType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f Float
+			err := f.Scan(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, f)
+		})
+	}
+}
+
+func TestBool_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Bool
+		wantErr error
+	}{
+		{name: "nil", src: nil, want: NewBool(false, false)},
+		{name: "bool", src: true, want: BoolFrom(true)},
+		{name: "int64 1", src: int64(1), want: BoolFrom(true)},
+		{name: "int64 0", src: int64(0), want: BoolFrom(false)},
+		{name: "uint8 1", src: uint8(1), want: BoolFrom(true)},
+		{name: "int64 2", src: int64(2), wantErr: strconv.ErrRange},
+		{name: "bytes 1", src: []byte("1"), want: BoolFrom(true)},
+		{name: "Y", src: "Y", want: BoolFrom(true)},
+		{name: "N", src: "N", want: BoolFrom(false)},
+		{name: "t", src: "t", want: BoolFrom(true)},
+		{name: "f", src: "f", want: BoolFrom(false)},
+		{name: "on", src: "ON", want: BoolFrom(true)},
+		{name: "off", src: "off", want: BoolFrom(false)},
+		{name: "blank", src: "", want: NewBool(false, false)},
+		{name: "invalid string", src: "maybe", wantErr: strconv.ErrSyntax},
+		{name: "unsupported", src: struct{}{}, wantErr: ErrUnsupportedType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Bool
+			err := b.Scan(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, b)
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package nulled
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDriver is a minimal database/sql driver. Every Exec stores its
+// arguments as the single row of the table, and every Query returns
+// that row, so values make a full round trip through database/sql.
+type fakeDriver struct {
+	mu  sync.Mutex
+	row []driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) {
+	return &fakeConn{d: d}, nil
+}
+
+func (d *fakeDriver) set(row []driver.Value) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.row = row
+}
+
+func (d *fakeDriver) get() []driver.Value {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]driver.Value(nil), d.row...)
+}
+
+type fakeConn struct {
+	d *fakeDriver
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{d: c.d}, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("fake driver: transactions are not supported")
+}
+
+type fakeStmt struct {
+	d *fakeDriver
+}
+
+func (s *fakeStmt) Close() error {
+	return nil
+}
+
+func (s *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.set(args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &fakeRows{row: s.d.get()}, nil
+}
+
+type fakeRows struct {
+	row  []driver.Value
+	done bool
+}
+
+func (r *fakeRows) Columns() []string {
+	columns := make([]string, len(r.row))
+	for i := range columns {
+		columns[i] = "c"
+	}
+	return columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.row)
+	return nil
+}
+
+var testDriver = &fakeDriver{}
+
+func init() {
+	sql.Register("nulled_fake", testDriver)
+}
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("nulled_fake", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestSQL_RoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		in   []any
+		want []any
+	}{
+		{
+			name: "valid",
+			in:   []any{StringFrom("hello"), IntFrom(42), FloatFrom(1.5), BoolFrom(true), TimeFrom(now)},
+			want: []any{StringFrom("hello"), IntFrom(42), FloatFrom(1.5), BoolFrom(true), TimeFrom(now)},
+		},
+		{
+			name: "zero values",
+			in:   []any{NewString("", true), IntFrom(0), FloatFrom(0), BoolFrom(false), NewTime(time.Time{}, true)},
+			want: []any{NewString("", false), IntFrom(0), FloatFrom(0), BoolFrom(false), NewTime(time.Time{}, false)},
+		},
+		{
+			name: "null",
+			in:   []any{NewString("", false), NewInt(0, false), NewFloat(0, false), NewBool(false, false), NewTime(time.Time{}, false)},
+			want: []any{NewString("", false), NewInt(0, false), NewFloat(0, false), NewBool(false, false), NewTime(time.Time{}, false)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := db.Exec("INSERT", tt.in...)
+			require.NoError(t, err)
+
+			var (
+				s  String
+				i  Int
+				f  Float
+				b  Bool
+				ti Time
+			)
+			err = db.QueryRow("SELECT").Scan(&s, &i, &f, &b, &ti)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, []any{s, i, f, b, ti})
+		})
+	}
+}
+
+func TestSQL_Value(t *testing.T) {
+	tests := []struct {
+		name  string
+		value driver.Valuer
+		want  driver.Value
+	}{
+		{name: "string", value: StringFrom("hello"), want: "hello"},
+		{name: "blank string", value: NewString(" ", true), want: nil},
+		{name: "null string", value: NewString("", false), want: nil},
+		{name: "int", value: IntFrom(42), want: int64(42)},
+		{name: "null int", value: NewInt(42, false), want: nil},
+		{name: "float", value: FloatFrom(1.5), want: 1.5},
+		{name: "null float", value: NewFloat(1.5, false), want: nil},
+		{name: "bool", value: BoolFrom(false), want: false},
+		{name: "null bool", value: NewBool(true, false), want: nil},
+		{name: "time", value: TimeFrom(testTime), want: testTime},
+		{name: "zero time", value: NewTime(time.Time{}, true), want: nil},
+		{name: "null time", value: NewTime(testTime, false), want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.value.Value()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestSQL_Scan(t *testing.T) {
+	var s String
+	assert.NoError(t, s.Scan([]byte(" hello ")))
+	assert.Equal(t, StringFrom("hello"), s)
+	assert.NoError(t, s.Scan(nil))
+	assert.False(t, s.Valid)
+
+	var i Int
+	assert.NoError(t, i.Scan(int64(7)))
+	assert.Equal(t, IntFrom(7), i)
+	assert.NoError(t, i.Scan(nil))
+	assert.False(t, i.Valid)
+
+	var ti Time
+	assert.NoError(t, ti.Scan(testTime))
+	assert.Equal(t, TimeFrom(testTime), ti)
+	assert.NoError(t, ti.Scan(time.Time{}))
+	assert.False(t, ti.Valid)
+	assert.NoError(t, ti.Scan(nil))
+	assert.False(t, ti.Valid)
+}
@@ -0,0 +1,194 @@
+package nulled
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// StrictInt, StrictFloat, StrictBool, StrictString and StrictTime are opt-in
+// variants for enforcing internal JSON contracts. They embed the regular
+// type and behave like it, except for JSON:
+//
+//   - only the JSON literal null decodes to null; "" is a valid empty
+//     StrictString and a type error for the other types;
+//   - non-standard spellings such as nil are syntax errors;
+//   - a value of the wrong JSON type, e.g. a string for a StrictInt, is
+//     reported as a *json.UnmarshalTypeError;
+//   - StrictTime only accepts RFC 3339 strings;
+//   - DefaultPolicy isn't applied in either direction, so values such as ""
+//     and 0 round-trip unchanged, and Normalize leaves them alone.
+type (
+	StrictInt struct {
+		Int
+	}
+	StrictFloat struct {
+		Float
+	}
+	StrictBool struct {
+		Bool
+	}
+	StrictString struct {
+		String
+	}
+	StrictTime struct {
+		Time
+	}
+)
+
+var jsonNull = []byte("null")
+
+// The Strict types would otherwise promote the policy methods of the type
+// they embed, and Normalize would apply DefaultPolicy to untagged fields,
+// while Policied would apply its policy to their JSON.
+
+func (i *StrictInt) applyPolicy(Policy) {}
+
+func (f *StrictFloat) applyPolicy(Policy) {}
+
+func (b *StrictBool) applyPolicy(Policy) {}
+
+func (m *StrictString) applyPolicy(Policy) {}
+
+func (t *StrictTime) applyPolicy(Policy) {}
+
+func (i StrictInt) marshalJSON(Policy) ([]byte, error) { return i.MarshalJSON() }
+
+func (f StrictFloat) marshalJSON(Policy) ([]byte, error) { return f.MarshalJSON() }
+
+func (b StrictBool) marshalJSON(Policy) ([]byte, error) { return b.MarshalJSON() }
+
+func (m StrictString) marshalJSON(Policy) ([]byte, error) { return m.MarshalJSON() }
+
+func (t StrictTime) marshalJSON(Policy) ([]byte, error) { return t.MarshalJSON() }
+
+func (i *StrictInt) unmarshalJSON(data []byte, _ Policy) error { return i.UnmarshalJSON(data) }
+
+func (f *StrictFloat) unmarshalJSON(data []byte, _ Policy) error { return f.UnmarshalJSON(data) }
+
+func (b *StrictBool) unmarshalJSON(data []byte, _ Policy) error { return b.UnmarshalJSON(data) }
+
+func (m *StrictString) unmarshalJSON(data []byte, _ Policy) error { return m.UnmarshalJSON(data) }
+
+func (t *StrictTime) unmarshalJSON(data []byte, _ Policy) error { return t.UnmarshalJSON(data) }
+
+// isJSONNull reports whether data is the JSON literal null.
+func isJSONNull(data []byte) bool {
+	return bytes.Equal(bytes.TrimSpace(data), jsonNull)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (i StrictInt) MarshalJSON() ([]byte, error) {
+	if !i.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(i.Int64)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (i *StrictInt) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		i.Int = NewInt(0, false)
+		return nil
+	}
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		i.Int = NewInt(0, false)
+		return err
+	}
+	i.Int = NewInt(n, true)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (f StrictFloat) MarshalJSON() ([]byte, error) {
+	if !f.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(f.Float64)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (f *StrictFloat) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		f.Float = NewFloat(0, false)
+		return nil
+	}
+	var n float64
+	if err := json.Unmarshal(data, &n); err != nil {
+		f.Float = NewFloat(0, false)
+		return err
+	}
+	f.Float = NewFloat(n, true)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b StrictBool) MarshalJSON() ([]byte, error) {
+	if !b.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(b.Bool.Bool)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *StrictBool) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		b.Bool = NewBool(false, false)
+		return nil
+	}
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		b.Bool = NewBool(false, false)
+		return err
+	}
+	b.Bool = NewBool(v, true)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (m StrictString) MarshalJSON() ([]byte, error) {
+	if !m.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(m.String.String)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (m *StrictString) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		m.String = NewString("", false)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		m.String = NewString("", false)
+		return err
+	}
+	m.String = NewString(s, true)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// The time is written in TimeOutputLocation, like Time.
+func (t StrictTime) MarshalJSON() ([]byte, error) {
+	if !t.Valid {
+		return jsonNull, nil
+	}
+	return json.Marshal(t.Time.output())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *StrictTime) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		t.Time = NewTime(time.Time{}, false)
+		return nil
+	}
+	var v time.Time
+	if err := json.Unmarshal(data, &v); err != nil {
+		t.Time = NewTime(time.Time{}, false)
+		return err
+	}
+	t.Time = NewTime(v, true)
+	return nil
+}
@@ -0,0 +1,144 @@
+package nulled
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictInt_UnmarshalJSON(t *testing.T) {
+	var i StrictInt
+	require.NoError(t, json.Unmarshal([]byte(`0`), &i))
+	assert.Equal(t, IntFrom(0), i.Int)
+	require.NoError(t, json.Unmarshal([]byte(`null`), &i))
+	assert.Equal(t, NewInt(0, false), i.Int)
+
+	var typeErr *json.UnmarshalTypeError
+	assert.True(t, errors.As(json.Unmarshal([]byte(`"123"`), &i), &typeErr))
+	assert.True(t, errors.As(json.Unmarshal([]byte(`""`), &i), &typeErr))
+	assert.True(t, errors.As(json.Unmarshal([]byte(`1.5`), &i), &typeErr))
+
+	var syntaxErr *json.SyntaxError
+	assert.True(t, errors.As(i.UnmarshalJSON([]byte(`nil`)), &syntaxErr))
+	assert.True(t, errors.As(json.Unmarshal([]byte(`nil`), &i), &syntaxErr))
+
+	// the regular type still accepts both
+	var regular Int
+	assert.NoError(t, regular.UnmarshalJSON([]byte(`nil`)))
+	assert.NoError(t, regular.UnmarshalJSON([]byte(`""`)))
+}
+
+func TestStrictString_JSON(t *testing.T) {
+	var s StrictString
+	require.NoError(t, json.Unmarshal([]byte(`""`), &s))
+	assert.Equal(t, NewString("", true), s.String)
+	require.NoError(t, json.Unmarshal([]byte(`" a "`), &s))
+	assert.Equal(t, NewString(" a ", true), s.String)
+	require.NoError(t, json.Unmarshal([]byte(`null`), &s))
+	assert.False(t, s.Valid)
+
+	var typeErr *json.UnmarshalTypeError
+	assert.True(t, errors.As(json.Unmarshal([]byte(`123`), &s), &typeErr))
+
+	data, err := json.Marshal(StrictString{NewString("", true)})
+	require.NoError(t, err)
+	assert.Equal(t, `""`, string(data))
+	data, err = json.Marshal(StrictString{})
+	require.NoError(t, err)
+	assert.Equal(t, `null`, string(data))
+}
+
+func TestStrictFloatAndBool_UnmarshalJSON(t *testing.T) {
+	var typeErr *json.UnmarshalTypeError
+
+	var f StrictFloat
+	require.NoError(t, json.Unmarshal([]byte(`1.5`), &f))
+	assert.Equal(t, FloatFrom(1.5), f.Float)
+	assert.True(t, errors.As(json.Unmarshal([]byte(`"1.5"`), &f), &typeErr))
+	assert.True(t, errors.As(json.Unmarshal([]byte(`""`), &f), &typeErr))
+
+	var b StrictBool
+	require.NoError(t, json.Unmarshal([]byte(`false`), &b))
+	assert.Equal(t, BoolFrom(false), b.Bool)
+	assert.True(t, errors.As(json.Unmarshal([]byte(`1`), &b), &typeErr))
+	assert.True(t, errors.As(json.Unmarshal([]byte(`"true"`), &b), &typeErr))
+}
+
+func TestStrictTime_UnmarshalJSON(t *testing.T) {
+	var ti StrictTime
+	require.NoError(t, json.Unmarshal([]byte(testTimeStr), &ti))
+	assert.Equal(t, TimeFrom(testTime), ti.Time)
+	require.NoError(t, json.Unmarshal([]byte(`null`), &ti))
+	assert.False(t, ti.Valid)
+
+	assert.Error(t, json.Unmarshal([]byte(`"2023-10-27 10:00:00"`), &ti))
+	assert.Error(t, json.Unmarshal([]byte(`""`), &ti))
+
+	var typeErr *json.UnmarshalTypeError
+	assert.True(t, errors.As(json.Unmarshal([]byte(`1698400800`), &ti), &typeErr))
+}
+
+func TestStrictTime_OutputLocation(t *testing.T) {
+	defer func(out *time.Location) { TimeOutputLocation = out }(TimeOutputLocation)
+	TimeOutputLocation = time.FixedZone("CET", 60*60)
+
+	ti := StrictTime{TimeFrom(time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC))}
+	data, err := json.Marshal(ti)
+	require.NoError(t, err)
+	assert.Equal(t, `"2023-10-27T11:00:00+01:00"`, string(data))
+
+	want, err := json.Marshal(ti.Time)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(data))
+}
+
+func TestStrict_Struct(t *testing.T) {
+	type event struct {
+		ID    StrictInt    `json:"id"`
+		Name  StrictString `json:"name"`
+		Score StrictFloat  `json:"score"`
+		OK    StrictBool   `json:"ok"`
+		At    StrictTime   `json:"at"`
+	}
+	in := event{
+		ID:    StrictInt{IntFrom(0)},
+		Name:  StrictString{NewString("", true)},
+		Score: StrictFloat{NewFloat(0, false)},
+		OK:    StrictBool{BoolFrom(true)},
+		At:    StrictTime{TimeFrom(time.Date(2023, 10, 27, 10, 0, 0, 0, time.UTC))},
+	}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":0,"name":"","score":null,"ok":true,"at":"2023-10-27T10:00:00Z"}`, string(data))
+
+	var out event
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestStrict_Normalize(t *testing.T) {
+	var in struct {
+		Name  StrictString `json:"name"`
+		Count StrictInt    `json:"count" nulled:"zero"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"","count":0}`), &in))
+	require.NoError(t, Normalize(&in))
+	assert.Equal(t, NewString("", true), in.Name.String)
+	assert.Equal(t, IntFrom(0), in.Count.Int)
+}
+
+func TestStrict_ResetOnError(t *testing.T) {
+	i := StrictInt{IntFrom(9)}
+	m := StrictString{StringFrom("abc")}
+	ti := StrictTime{TimeFrom(testTime)}
+	assert.Error(t, json.Unmarshal([]byte(`"9"`), &i))
+	assert.Error(t, json.Unmarshal([]byte(`1`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`"tomorrow"`), &ti))
+	assert.False(t, i.Valid)
+	assert.False(t, m.Valid)
+	assert.False(t, ti.Valid)
+}
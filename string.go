@@ -1,13 +1,14 @@
 package nulled
 
 import (
-	"bytes"
-	"encoding/gob"
-	"encoding/json"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/xml"
 	"net/url"
 	"strings"
 
 	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
 )
 
 type String null.String
@@ -15,12 +16,11 @@ type String null.String
 func NewString(s string, valid bool) String {
 	return String(null.NewString(s, valid))
 }
+
+// StringFrom creates a String from s under DefaultPolicy, which trims it
+// and treats a blank string as null.
 func StringFrom(s string) String {
-	s = strings.TrimSpace(s)
-	if s == "" {
-		return NewString("", false)
-	}
-	return NewString(s, true)
+	return NewString(s, true).WithPolicy(DefaultPolicy)
 }
 
 func StringFromPtr(s *string) String {
@@ -37,73 +37,203 @@ func (m String) ValueOrZero() string {
 	return m.String
 }
 
+// IsZero reports whether the value is null.
+func (m String) IsZero() bool {
+	return !m.Valid
+}
+
+// asValue returns m as the Value it is built on.
+func (m String) asValue() Value[string] {
+	return NewValue(m.String, m.Valid)
+}
+
+func stringFromValue(v Value[string]) String {
+	return NewString(v.V, v.Valid)
+}
+
+// WithPolicy returns the value cleaned up and nulled according to p.
+func (m String) WithPolicy(p Policy) String {
+	return stringFromValue(m.asValue().WithPolicy(p))
+}
+
+func (m *String) applyPolicy(p Policy) {
+	*m = m.WithPolicy(p)
+}
+
 func (m String) EncodeValues(key string, v *url.Values) error {
-	if !m.Valid || strings.TrimSpace(m.String) == "" {
-		return nil
-	}
-	v.Set(key, m.String)
-	return nil
+	return m.encodeValues(key, v, DefaultPolicy)
+}
+
+func (m String) encodeValues(key string, v *url.Values, p Policy) error {
+	return m.asValue().encodeValues(key, v, p)
 }
 
 func (m String) MarshalJSON() ([]byte, error) {
+	return m.marshalJSON(DefaultPolicy)
+}
+
+func (m String) marshalJSON(p Policy) ([]byte, error) {
+	return m.asValue().marshalJSON(p)
+}
+
+func (m *String) UnmarshalJSON(data []byte) error {
+	return m.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (m *String) unmarshalJSON(data []byte, p Policy) error {
+	v := m.asValue()
+	err := v.unmarshalJSON(data, p)
+	*m = stringFromValue(v)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (m String) MarshalText() ([]byte, error) {
+	return m.marshalText(DefaultPolicy)
+}
+
+func (m String) marshalText(p Policy) ([]byte, error) {
+	return m.asValue().marshalText(p)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (m *String) UnmarshalText(text []byte) error {
+	return m.unmarshalText(text, DefaultPolicy)
+}
+
+func (m *String) unmarshalText(text []byte, p Policy) error {
+	v := m.asValue()
+	err := v.unmarshalText(text, p)
+	*m = stringFromValue(v)
+	return err
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (m String) MarshalYAML() (any, error) {
+	return m.marshalYAML(DefaultPolicy)
+}
+
+func (m String) marshalYAML(p Policy) (any, error) {
+	m = m.WithPolicy(p)
 	if !m.Valid {
-		return []byte("null"), nil
+		return nil, nil
 	}
-	return json.Marshal(m.String)
+	return m.String, nil
 }
 
-func (m *String) UnmarshalJSON(data []byte) error {
-	var s *string
-	if err := json.Unmarshal(data, &s); err != nil {
-		return err
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+func (m *String) UnmarshalYAML(value *yaml.Node) error {
+	return m.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (m *String) unmarshalYAML(value *yaml.Node, p Policy) error {
+	if isYAMLNull(value) {
+		*m = NewString("", false)
+		return nil
 	}
-	if s == nil {
-		m.Valid = false
-		m.String = ""
-	} else {
-		// an empty string is considered null
-		m.Valid = *s != ""
-		m.String = *s
+	var s string
+	if err := value.Decode(&s); err != nil {
+		return err
 	}
+	*m = NewString(s, true).WithPolicy(p)
 	return nil
 }
 
-// UnmarshalText implements the encoding.TextUnmarshaler interface.
-func (m *String) UnmarshalText(text []byte) error {
-	s := string(text)
-	// an empty string is considered null
-	if s == "" {
-		m.Valid = false
-		m.String = ""
-	} else {
-		m.Valid = true
-		m.String = s
+// MarshalXML implements the xml.Marshaler interface.
+func (m String) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	m = m.WithPolicy(DefaultPolicy)
+	return marshalXMLElement(e, start, m.String, m.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (m *String) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
+	if err != nil {
+		return err
 	}
+	*m = NewString(s, valid).WithPolicy(DefaultPolicy)
 	return nil
 }
 
-func (m String) GobEncode() ([]byte, error) {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	err := enc.Encode(m.String)
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (m String) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	m = m.WithPolicy(DefaultPolicy)
+	if !m.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: m.String}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (m *String) UnmarshalXMLAttr(attr xml.Attr) error {
+	return m.UnmarshalText([]byte(attr.Value))
+}
+
+// Scan implements the sql.Scanner interface.
+// Values are cleaned up with DefaultPolicy, same as StringFrom.
+func (m *String) Scan(value any) error {
+	return m.scan(value, DefaultPolicy)
+}
+
+func (m *String) scan(value any, p Policy) error {
+	v := m.asValue()
+	err := v.scan(value, p)
+	*m = stringFromValue(v)
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+// Values that are null under DefaultPolicy are written as NULL.
+func (m String) Value() (driver.Value, error) {
+	return m.value(DefaultPolicy)
+}
+
+func (m String) value(p Policy) (driver.Value, error) {
+	return m.asValue().value(p)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (m String) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(m.Valid)
+	if !m.Valid {
+		return b, nil
+	}
+	return stringCodec{}.EncodeBinary(b, m.String)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (m *String) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !valid {
+		*m = NewString("", false)
+		return nil
 	}
-	err = enc.Encode(m.Valid)
+	x, err := stringCodec{}.DecodeBinary(p)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return buf.Bytes(), nil
+	*m = NewString(x, true)
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (m String) GobEncode() ([]byte, error) {
+	return m.MarshalBinary()
 }
 
+// GobDecode implements the gob.GobDecoder interface.
+// It also reads the format written by earlier versions of GobEncode.
 func (m *String) GobDecode(data []byte) error {
-	buf := bytes.NewBuffer(data)
-	dec := gob.NewDecoder(buf)
-	err := dec.Decode(&m.String)
-	if err != nil {
-		return err
+	if isLegacyGob(data) {
+		return decodeLegacyGob(data, &m.String, &m.Valid)
 	}
-	return dec.Decode(&m.Valid)
+	return m.UnmarshalBinary(data)
 }
 
 func (m String) NullValue() null.String {
@@ -112,3 +242,27 @@ func (m String) NullValue() null.String {
 	}
 	return null.NewString("", false)
 }
+
+// stringCodec is the codec of String.
+type stringCodec struct{ DefaultCodec[string] }
+
+func (stringCodec) Normalize(s string, p Policy) (string, bool) {
+	s = p.text(s)
+	if (p.EmptyAsNull && s == "") || (p.WhitespaceAsNull && strings.TrimSpace(s) == "") {
+		return "", false
+	}
+	return s, true
+}
+
+func (stringCodec) EncodeBinary(b []byte, s string) ([]byte, error) {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...), nil
+}
+
+func (stringCodec) DecodeBinary(data []byte) (string, error) {
+	n, size := binary.Uvarint(data)
+	if size <= 0 || uint64(len(data)-size) != n {
+		return "", ErrInvalidBinary
+	}
+	return string(data[size:]), nil
+}
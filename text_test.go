@@ -0,0 +1,118 @@
+package nulled
+
+import (
+	"encoding"
+	"encoding/json"
+	"flag"
+	"math"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type textCodec interface {
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}
+
+func TestText_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		value textCodec
+		text  string
+		empty func() textCodec
+	}{
+		{name: "string", value: ptr(StringFrom("hello world")), text: "hello world", empty: func() textCodec { return new(String) }},
+		{name: "null string", value: ptr(NewString("", false)), text: "", empty: func() textCodec { return new(String) }},
+		{name: "int", value: ptr(IntFrom(-42)), text: "-42", empty: func() textCodec { return new(Int) }},
+		{name: "zero int", value: ptr(IntFrom(0)), text: "0", empty: func() textCodec { return new(Int) }},
+		{name: "max int", value: ptr(IntFrom(math.MaxInt64)), text: "9223372036854775807", empty: func() textCodec { return new(Int) }},
+		{name: "null int", value: ptr(NewInt(0, false)), text: "", empty: func() textCodec { return new(Int) }},
+		{name: "float", value: ptr(FloatFrom(0.1)), text: "0.1", empty: func() textCodec { return new(Float) }},
+		{name: "small float", value: ptr(FloatFrom(1.5e-10)), text: "0.00000000015", empty: func() textCodec { return new(Float) }},
+		{name: "null float", value: ptr(NewFloat(0, false)), text: "", empty: func() textCodec { return new(Float) }},
+		{name: "true", value: ptr(BoolFrom(true)), text: "true", empty: func() textCodec { return new(Bool) }},
+		{name: "false", value: ptr(BoolFrom(false)), text: "false", empty: func() textCodec { return new(Bool) }},
+		{name: "null bool", value: ptr(NewBool(false, false)), text: "", empty: func() textCodec { return new(Bool) }},
+		{name: "time", value: ptr(TimeFrom(testTime)), text: testTimeText, empty: func() textCodec { return new(Time) }},
+		{
+			name:  "time with nanoseconds and zone",
+			value: ptr(TimeFrom(time.Date(2023, 10, 27, 18, 0, 0, 123456789, time.FixedZone("", 8*60*60)))),
+			text:  "2023-10-27T18:00:00.123456789+08:00",
+			empty: func() textCodec { return new(Time) },
+		},
+		{name: "null time", value: ptr(NewTime(time.Time{}, false)), text: "", empty: func() textCodec { return new(Time) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.value.MarshalText()
+			require.NoError(t, err)
+			assert.Equal(t, tt.text, string(text))
+
+			decoded := tt.empty()
+			require.NoError(t, decoded.UnmarshalText(text))
+			assert.Equal(t, tt.value, decoded)
+		})
+	}
+}
+
+func TestText_EncodeValuesRoundTrip(t *testing.T) {
+	values := url.Values{}
+	require.NoError(t, BoolFrom(true).EncodeValues("b", &values))
+	require.NoError(t, FloatFrom(1.5).EncodeValues("f", &values))
+	require.NoError(t, IntFrom(7).EncodeValues("i", &values))
+	require.NoError(t, StringFrom("hi").EncodeValues("s", &values))
+	require.NoError(t, TimeFrom(testTime).EncodeValues("t", &values))
+
+	var (
+		b  Bool
+		f  Float
+		i  Int
+		s  String
+		ti Time
+	)
+	require.NoError(t, b.UnmarshalText([]byte(values.Get("b"))))
+	require.NoError(t, f.UnmarshalText([]byte(values.Get("f"))))
+	require.NoError(t, i.UnmarshalText([]byte(values.Get("i"))))
+	require.NoError(t, s.UnmarshalText([]byte(values.Get("s"))))
+	require.NoError(t, ti.UnmarshalText([]byte(values.Get("t"))))
+	assert.Equal(t, BoolFrom(true), b)
+	assert.Equal(t, FloatFrom(1.5), f)
+	assert.Equal(t, IntFrom(7), i)
+	assert.Equal(t, StringFrom("hi"), s)
+	assert.Equal(t, TimeFrom(testTime), ti)
+}
+
+func TestInt_UnmarshalText(t *testing.T) {
+	var i Int
+	assert.NoError(t, i.UnmarshalText([]byte("null")))
+	assert.False(t, i.Valid)
+	assert.Error(t, i.UnmarshalText([]byte("1.5")))
+	assert.False(t, i.Valid)
+}
+
+func TestText_MapKey(t *testing.T) {
+	data, err := json.Marshal(map[Int]string{IntFrom(1): "one"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"1":"one"}`, string(data))
+
+	var m map[Int]string
+	require.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, map[Int]string{IntFrom(1): "one"}, m)
+}
+
+func TestText_FlagTextVar(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var limit Int
+	fs.TextVar(&limit, "limit", NewInt(0, false), "")
+	require.NoError(t, fs.Parse([]string{"-limit", "25"}))
+	assert.Equal(t, IntFrom(25), limit)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -1,35 +1,186 @@
 package nulled
 
 import (
-	"bytes"
-	"encoding/gob"
+	"database/sql/driver"
 	"encoding/json"
+	"encoding/xml"
+	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
 )
 
 type Time null.Time
 
-func NewTime(t time.Time, valid bool) Time {
-	return Time(null.NewTime(t, valid))
+var (
+	// TimeLayouts are the layouts tried, in order, when a Time is decoded from
+	// a JSON string, text or a text column such as SQLite TEXT. Layouts
+	// without zone information are interpreted in TimeLocation. Text that
+	// matches none of them is tried as a Unix timestamp, see TimeEpochUnit.
+	TimeLayouts = []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04",
+		time.DateOnly,
+		"2006/01/02 15:04:05",
+		"2006/01/02",
+		"2006年1月2日 15时04分05秒",
+		"2006年1月2日 15:04:05",
+		"2006年1月2日",
+	}
+
+	// TimeLocation is the location attached to decoded times that carry no
+	// zone information, such as "2006-01-02 15:04:05".
+	TimeLocation = time.UTC
+
+	// TimeOutputLocation, if not nil, is the location every Time is converted
+	// to before it is encoded as JSON, text, YAML, XML or url.Values. When it
+	// is nil times are encoded in the location they carry.
+	TimeOutputLocation *time.Location
+
+	// TimeValueLayout makes Value return the time formatted with this layout in
+	// TimeLocation instead of a time.Time, for drivers that store datetimes as
+	// text. Leave it empty to return a time.Time.
+	TimeValueLayout = ""
+
+	// TimeEpochUnit is the unit of Unix timestamps given as JSON numbers or
+	// numeric text.
+	TimeEpochUnit = EpochAuto
+
+	// TimePrecision, if not zero, is the precision times are reduced to by
+	// NewTime, TimeFrom, Value and the encoders, so that values survive a
+	// round trip through a database column of that precision unchanged, e.g.
+	// time.Microsecond for PostgreSQL or time.Second for MySQL DATETIME.
+	// The binary and gob encodings keep times exact, and Date and TimeOfDay
+	// take their fields from the unreduced time.
+	TimePrecision time.Duration
+
+	// TimeRound makes TimePrecision round times to the nearest multiple
+	// instead of truncating them.
+	TimeRound = false
+)
+
+// EpochUnit is the unit of a Unix timestamp.
+type EpochUnit int
+
+const (
+	// EpochAuto reads timestamps of at least 1e11 in absolute value as
+	// milliseconds and smaller ones as seconds. 1e11 seconds is in the year
+	// 5138, while 1e11 milliseconds is in 1973.
+	EpochAuto EpochUnit = iota
+	EpochSeconds
+	EpochMilliseconds
+)
+
+const epochMillisThreshold = 1e11
+
+// parseEpoch parses s as an integer Unix timestamp in TimeEpochUnit.
+func parseEpoch(s string) (time.Time, bool) {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch TimeEpochUnit {
+	case EpochMilliseconds:
+		return time.UnixMilli(n).In(TimeLocation), true
+	case EpochAuto:
+		if n >= epochMillisThreshold || n <= -epochMillisThreshold {
+			return time.UnixMilli(n).In(TimeLocation), true
+		}
+	}
+	return time.Unix(n, 0).In(TimeLocation), true
 }
 
-func TimeFrom(t time.Time) Time {
-	if t.IsZero() {
-		return NewTime(time.Time{}, false)
+// isZeroDate reports whether s is a MySQL zero date such as
+// "0000-00-00" or "0000-00-00 00:00:00".
+func isZeroDate(s string) bool {
+	return strings.HasPrefix(s, "0000-00-00")
+}
+
+// parseTime parses s with TimeLayouts, falling back to a Unix timestamp.
+// Blank text and MySQL zero dates are reported as not valid.
+func parseTime(s string) (time.Time, bool, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || isZeroDate(s) {
+		return time.Time{}, false, nil
 	}
+	if t, ok := parseLayouts(s); ok {
+		return t, !t.IsZero(), nil
+	}
+	if t, ok := parseEpoch(s); ok {
+		return t, true, nil
+	}
+	return time.Time{}, false, strconv.ErrSyntax
+}
+
+// parseLayouts parses s with the first of TimeLayouts that matches it.
+func parseLayouts(s string) (time.Time, bool) {
+	for _, layout := range TimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, TimeLocation); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// outputLocation returns t converted to out, if out is not nil.
+func outputLocation(t time.Time, out *time.Location) time.Time {
+	if out == nil {
+		return t
+	}
+	return t.In(out)
+}
+
+// output returns the time to encode, in TimeOutputLocation.
+func (t Time) output() time.Time {
+	return outputLocation(reducePrecision(t.Time), TimeOutputLocation)
+}
+
+// reducePrecision strips the monotonic clock reading from t and truncates or
+// rounds it to TimePrecision.
+func reducePrecision(t time.Time) time.Time {
+	t = t.Round(0)
+	switch {
+	case TimePrecision <= 0:
+		return t
+	case TimeRound:
+		return t.Round(TimePrecision)
+	}
+	return t.Truncate(TimePrecision)
+}
+
+// NewTime creates a Time from t, reduced to TimePrecision and without its
+// monotonic clock reading.
+func NewTime(t time.Time, valid bool) Time {
+	return Time(null.NewTime(reducePrecision(t), valid))
+}
 
-	return NewTime(t, true)
+// TimeFrom creates a Time from t. The zero time is null.
+func TimeFrom(t time.Time) Time {
+	return NewTime(t, true).WithPolicy(DefaultPolicy)
 }
 
 func TimeFromPtr(t *time.Time) Time {
-	if t == nil || t.IsZero() {
+	if t == nil {
 		return NewTime(time.Time{}, false)
 	}
 
-	return NewTime(*t, true)
+	return TimeFrom(*t)
+}
+
+// Equal reports whether t and u are both null, or both valid and the same
+// instant, whatever their locations.
+func (t Time) Equal(u Time) bool {
+	if !t.Valid || !u.Valid {
+		return t.Valid == u.Valid
+	}
+	return t.Time.Equal(u.Time)
 }
 
 func (t Time) ValueOrZero() time.Time {
@@ -40,72 +191,234 @@ func (t Time) ValueOrZero() time.Time {
 	return t.Time
 }
 
+// IsZero reports whether the value is null.
+func (t Time) IsZero() bool {
+	return !t.Valid
+}
+
+// asValue returns t as the Value it is built on.
+func (t Time) asValue() Value[time.Time] {
+	return NewValue(t.Time, t.Valid)
+}
+
+func timeFromValue(v Value[time.Time]) Time {
+	return NewTime(v.V, v.Valid)
+}
+
+// exactTime creates a Time from t without reducing its precision, for
+// values that must be kept as they are, such as decoded binary data.
+func exactTime(t time.Time, valid bool) Time {
+	return Time(null.NewTime(t.Round(0), valid))
+}
+
+// isNull reports whether t is null or holds the zero time, which is null
+// under every policy. Unlike WithPolicy it leaves the precision alone.
+func (t Time) isNull() bool {
+	return !t.Valid || t.Time.IsZero()
+}
+
+// WithPolicy returns the value nulled according to p. The zero time is
+// null under every policy.
+func (t Time) WithPolicy(p Policy) Time {
+	return timeFromValue(t.asValue().WithPolicy(p))
+}
+
+func (t *Time) applyPolicy(p Policy) {
+	*t = t.WithPolicy(p)
+}
+
 func (t Time) EncodeValues(key string, v *url.Values) error {
-	if !t.Valid {
-		return nil
-	}
-	v.Set(key, t.Time.Format("2006-01-02 15:04:05"))
-	return nil
+	return t.encodeValues(key, v, DefaultPolicy)
+}
+
+func (t Time) encodeValues(key string, v *url.Values, p Policy) error {
+	return t.asValue().encodeValues(key, v, p)
 }
 
 func (t Time) MarshalJSON() ([]byte, error) {
-	if !t.Valid {
-		return []byte("null"), nil
-	}
-	return json.Marshal(t.Time)
+	return t.marshalJSON(DefaultPolicy)
+}
+
+func (t Time) marshalJSON(p Policy) ([]byte, error) {
+	return t.asValue().marshalJSON(p)
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// Strings are parsed like UnmarshalText, numbers as Unix timestamps in
+// TimeEpochUnit.
 func (t *Time) UnmarshalJSON(data []byte) error {
-	var tt null.Time
-	if err := json.Unmarshal(data, &tt); err != nil {
-		t.Valid = false
-		return err
+	return t.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (t *Time) unmarshalJSON(data []byte, p Policy) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		return t.unmarshalText([]byte(s), p)
 	}
-	t.Time = tt.Time
-	t.Valid = tt.Valid
-	return nil
+	v := t.asValue()
+	err := v.unmarshalJSON(data, p)
+	*t = timeFromValue(v)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A valid time is encoded as RFC 3339 with nanoseconds, a null value as
+// empty text.
+func (t Time) MarshalText() ([]byte, error) {
+	return t.marshalText(DefaultPolicy)
+}
+
+func (t Time) marshalText(p Policy) ([]byte, error) {
+	return t.asValue().marshalText(p)
 }
 
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
+// The text is parsed with TimeLayouts, so both RFC 3339 and the
+// "2006-01-02 15:04:05" format written by EncodeValues are accepted, and
+// numeric text is read as a Unix timestamp.
 func (t *Time) UnmarshalText(text []byte) error {
-	s := string(text)
-	if s == "" || s == "null" {
-		t.Valid = false
+	return t.unmarshalText(text, DefaultPolicy)
+}
+
+func (t *Time) unmarshalText(text []byte, p Policy) error {
+	v := t.asValue()
+	err := v.unmarshalText(text, p)
+	*t = timeFromValue(v)
+	return err
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (t Time) MarshalYAML() (any, error) {
+	return t.marshalYAML(DefaultPolicy)
+}
+
+func (t Time) marshalYAML(p Policy) (any, error) {
+	t = t.WithPolicy(p)
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.output(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The value is parsed the same way as UnmarshalText.
+func (t *Time) UnmarshalYAML(value *yaml.Node) error {
+	return t.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (t *Time) unmarshalYAML(value *yaml.Node, p Policy) error {
+	if isYAMLNull(value) {
+		*t = NewTime(time.Time{}, false)
 		return nil
 	}
-	// Use time.RFC3339 by default for robust parsing
-	parsedTime, err := time.Parse(time.RFC3339, s)
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("yaml: line %d: cannot unmarshal %s into nulled.Time", value.Line, value.ShortTag())
+	}
+	return t.unmarshalText([]byte(value.Value), p)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (t Time) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	t = t.WithPolicy(DefaultPolicy)
+	return marshalXMLElement(e, start, t.output().Format(time.RFC3339Nano), t.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (t *Time) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
 	if err != nil {
-		t.Valid = false
 		return err
 	}
-	t.Time = parsedTime
-	t.Valid = true
-	return nil
+	if !valid {
+		*t = NewTime(time.Time{}, false)
+		return nil
+	}
+	return t.UnmarshalText([]byte(strings.TrimSpace(s)))
 }
 
-func (t Time) GobEncode() ([]byte, error) {
-	var buf bytes.Buffer
-	enc := gob.NewEncoder(&buf)
-	err := enc.Encode(t.Time)
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (t Time) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	t = t.WithPolicy(DefaultPolicy)
+	if !t.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: t.output().Format(time.RFC3339Nano)}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (t *Time) UnmarshalXMLAttr(attr xml.Attr) error {
+	return t.UnmarshalText([]byte(strings.TrimSpace(attr.Value)))
+}
+
+// Scan implements the sql.Scanner interface.
+// Besides time.Time it accepts text in string or []byte form, parsed with
+// TimeLayouts. NULL, the zero time, blank text and MySQL zero dates such as
+// "0000-00-00 00:00:00" are scanned as null.
+func (t *Time) Scan(value any) error {
+	return t.scan(value, DefaultPolicy)
+}
+
+func (t *Time) scan(value any, p Policy) error {
+	v := t.asValue()
+	err := v.scan(value, p)
+	*t = timeFromValue(v)
+	return err
+}
+
+// Value implements the driver.Valuer interface.
+// A null or zero time is written as NULL. If TimeValueLayout is set the time
+// is written as text in TimeLocation, otherwise as a time.Time, reduced to
+// TimePrecision either way.
+func (t Time) Value() (driver.Value, error) {
+	return t.value(DefaultPolicy)
+}
+
+func (t Time) value(p Policy) (driver.Value, error) {
+	return t.asValue().value(p)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t Time) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(t.Valid)
+	if !t.Valid {
+		return b, nil
+	}
+	return timeCodec{}.EncodeBinary(b, t.Time)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+// The time is kept exactly as written, whatever TimePrecision is.
+func (t *Time) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !valid {
+		*t = NewTime(time.Time{}, false)
+		return nil
 	}
-	err = enc.Encode(t.Valid)
+	x, err := timeCodec{}.DecodeBinary(p)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return buf.Bytes(), nil
+	*t = exactTime(x, true)
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (t Time) GobEncode() ([]byte, error) {
+	return t.MarshalBinary()
 }
 
+// GobDecode implements the gob.GobDecoder interface.
+// It also reads the format written by earlier versions of GobEncode.
 func (t *Time) GobDecode(data []byte) error {
-	buf := bytes.NewBuffer(data)
-	dec := gob.NewDecoder(buf)
-	err := dec.Decode(&t.Time)
-	if err != nil {
-		return err
+	if isLegacyGob(data) {
+		return decodeLegacyGob(data, &t.Time, &t.Valid)
 	}
-	return dec.Decode(&t.Valid)
+	return t.UnmarshalBinary(data)
 }
 
 func (t Time) NullValue() null.Time {
@@ -114,3 +427,93 @@ func (t Time) NullValue() null.Time {
 	}
 	return null.NewTime(time.Time{}, false)
 }
+
+// timeCodec is the codec of Time.
+type timeCodec struct{ DefaultCodec[time.Time] }
+
+// Normalize treats the zero time as null.
+func (timeCodec) Normalize(t time.Time, p Policy) (time.Time, bool) {
+	return t, !t.IsZero()
+}
+
+func (timeCodec) EncodeJSON(t time.Time) ([]byte, error) {
+	return json.Marshal(NewTime(t, true).output())
+}
+
+func (c timeCodec) DecodeJSON(data []byte) (time.Time, bool, error) {
+	v, err := decodeLenientJSON(data, "Time")
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	switch s := v.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case string:
+		return c.DecodeText(s)
+	case json.Number:
+		t, ok := parseEpoch(string(s))
+		if !ok {
+			return time.Time{}, false, &ScanError{Src: string(s), Type: "Time", Err: strconv.ErrSyntax}
+		}
+		return t, true, nil
+	}
+	return time.Time{}, false, &ScanError{Src: v, Type: "Time", Err: ErrUnsupportedType}
+}
+
+func (timeCodec) EncodeText(t time.Time) ([]byte, error) {
+	return NewTime(t, true).output().MarshalText()
+}
+
+func (timeCodec) DecodeText(s string) (time.Time, bool, error) {
+	if s == "null" {
+		return time.Time{}, false, nil
+	}
+	t, valid, err := parseTime(s)
+	if err != nil {
+		return time.Time{}, false, &ScanError{Src: s, Type: "Time", Err: err}
+	}
+	return t, valid, nil
+}
+
+func (timeCodec) EncodeValue(t time.Time) (string, error) {
+	return NewTime(t, true).output().Format("2006-01-02 15:04:05"), nil
+}
+
+func (timeCodec) Scan(src any) (time.Time, bool, error) {
+	if t, ok := src.(time.Time); ok {
+		return t, true, nil
+	}
+	s, ok := toText(src)
+	if !ok {
+		return time.Time{}, false, &ScanError{Src: src, Type: "Time", Err: ErrUnsupportedType}
+	}
+	t, valid, err := parseTime(s)
+	if err != nil {
+		return time.Time{}, false, &ScanError{Src: src, Type: "Time", Err: err}
+	}
+	return t, valid, nil
+}
+
+func (timeCodec) Value(t time.Time) (driver.Value, error) {
+	t = reducePrecision(t)
+	if TimeValueLayout != "" {
+		return t.In(TimeLocation).Format(TimeValueLayout), nil
+	}
+	return t, nil
+}
+
+func (timeCodec) EncodeBinary(b []byte, t time.Time) ([]byte, error) {
+	data, err := t.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(b, data...), nil
+}
+
+func (timeCodec) DecodeBinary(data []byte) (time.Time, error) {
+	var t time.Time
+	if err := t.UnmarshalBinary(data); err != nil {
+		return t, ErrInvalidBinary
+	}
+	return t, nil
+}
@@ -66,6 +66,15 @@ func TestTime_UnmarshalJSON(t *testing.T) {
 		{name: "valid time", inputJSON: []byte(testTimeStr), want: NewTime(testTime, true)},
 		{name: "null", inputJSON: []byte(`null`), want: NewTime(time.Time{}, false)},
 		{name: "invalid json", inputJSON: []byte(`"not a time"`), wantErr: true},
+		{name: "date time", inputJSON: []byte(`"2023-10-27 10:00:00"`), want: NewTime(testTime, true)},
+		{name: "locale layout", inputJSON: []byte(`"2023年10月27日 10时00分00秒"`), want: NewTime(testTime, true)},
+		{name: "date only", inputJSON: []byte(`"2023/10/27"`), want: NewTime(time.Date(2023, 10, 27, 0, 0, 0, 0, time.UTC), true)},
+		{name: "empty string", inputJSON: []byte(`""`), want: NewTime(time.Time{}, false)},
+		{name: "epoch seconds", inputJSON: []byte(`1698400800`), want: NewTime(testTime, true)},
+		{name: "epoch milliseconds", inputJSON: []byte(`1698400800000`), want: NewTime(testTime, true)},
+		{name: "epoch string", inputJSON: []byte(`"1698400800"`), want: NewTime(testTime, true)},
+		{name: "fractional number", inputJSON: []byte(`1698400800.5`), wantErr: true},
+		{name: "bool", inputJSON: []byte(`true`), wantErr: true},
 	}
 
 	for _, tt := range tests {
@@ -84,6 +93,23 @@ func TestTime_UnmarshalJSON(t *testing.T) {
 	}
 }
 
+func TestTime_EpochUnit(t *testing.T) {
+	defer func(u EpochUnit) { TimeEpochUnit = u }(TimeEpochUnit)
+
+	var ti Time
+	TimeEpochUnit = EpochMilliseconds
+	assert.NoError(t, json.Unmarshal([]byte(`1698400800`), &ti))
+	assert.Equal(t, time.UnixMilli(1698400800).UTC(), ti.Time)
+
+	TimeEpochUnit = EpochSeconds
+	assert.NoError(t, json.Unmarshal([]byte(`1698400800000`), &ti))
+	assert.Equal(t, time.Unix(1698400800000, 0).UTC(), ti.Time)
+
+	TimeEpochUnit = EpochAuto
+	assert.NoError(t, ti.UnmarshalText([]byte(`-1000`)))
+	assert.Equal(t, time.Unix(-1000, 0).UTC(), ti.Time)
+}
+
 func TestTime_UnmarshalText(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -95,6 +121,9 @@ func TestTime_UnmarshalText(t *testing.T) {
 		{name: "empty bytes", inputText: []byte(``), want: NewTime(time.Time{}, false)},
 		{name: "null string", inputText: []byte(`null`), want: NewTime(time.Time{}, false)},
 		{name: "invalid string", inputText: []byte(`abc`), wantErr: true},
+		{name: "EncodeValues layout", inputText: []byte(`2023-10-27 10:00:00`), want: NewTime(testTime, true)},
+		{name: "locale date", inputText: []byte(`2023年10月27日`), want: NewTime(time.Date(2023, 10, 27, 0, 0, 0, 0, time.UTC), true)},
+		{name: "epoch milliseconds", inputText: []byte(`1698400800000`), want: NewTime(testTime, true)},
 	}
 
 	for _, tt := range tests {
@@ -175,3 +204,173 @@ func TestTime_NullValue(t *testing.T) {
 	assert.False(t, invalidTime.NullValue().Valid)
 	assert.True(t, invalidTime.NullValue().Time.IsZero())
 }
+
+func TestTime_Scan(t *testing.T) {
+	shanghai := time.FixedZone("Asia/Shanghai", 8*60*60)
+	tests := []struct {
+		name     string
+		src      any
+		location *time.Location
+		want     Time
+		wantErr  bool
+	}{
+		{name: "nil", src: nil, want: NewTime(time.Time{}, false)},
+		{name: "time", src: testTime, want: NewTime(testTime, true)},
+		{name: "zero time", src: time.Time{}, want: NewTime(time.Time{}, false)},
+		{name: "RFC3339 string", src: testTimeText, want: NewTime(testTime, true)},
+		{name: "datetime bytes", src: []byte("2023-10-27 10:00:00"), want: NewTime(testTime, true)},
+		{name: "datetime with fraction", src: "2023-10-27 10:00:00.5", want: NewTime(testTime.Add(500*time.Millisecond), true)},
+		{name: "datetime with offset", src: "2023-10-27 18:00:00+08:00", want: NewTime(testTime, true)},
+		{name: "date", src: "2023-10-27", want: NewTime(time.Date(2023, 10, 27, 0, 0, 0, 0, time.UTC), true)},
+		{name: "location", src: "2023-10-27 18:00:00", location: shanghai, want: NewTime(testTime, true)},
+		{name: "mysql zero datetime", src: []byte("0000-00-00 00:00:00"), want: NewTime(time.Time{}, false)},
+		{name: "mysql zero date", src: "0000-00-00", want: NewTime(time.Time{}, false)},
+		{name: "blank", src: "", want: NewTime(time.Time{}, false)},
+		{name: "invalid string", src: "yesterday", wantErr: true},
+		{name: "unsupported", src: 1.5, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.location != nil {
+				defer func(loc *time.Location) { TimeLocation = loc }(TimeLocation)
+				TimeLocation = tt.location
+			}
+			var ti Time
+			err := ti.Scan(tt.src)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want.Valid, ti.Valid)
+			assert.True(t, tt.want.Time.Equal(ti.Time))
+		})
+	}
+}
+
+func TestTime_Value(t *testing.T) {
+	v, err := NewTime(testTime, true).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, testTime, v)
+
+	v, err = NewTime(time.Time{}, false).Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	defer func(layout string, loc *time.Location) {
+		TimeValueLayout = layout
+		TimeLocation = loc
+	}(TimeValueLayout, TimeLocation)
+	TimeValueLayout = time.DateTime
+	TimeLocation = time.FixedZone("Asia/Shanghai", 8*60*60)
+
+	v, err = NewTime(testTime, true).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "2023-10-27 18:00:00", v)
+
+	var ti Time
+	assert.NoError(t, ti.Scan(v))
+	assert.True(t, testTime.Equal(ti.Time))
+}
+
+func TestTime_Locations(t *testing.T) {
+	defer func(in, out *time.Location) { TimeLocation, TimeOutputLocation = in, out }(TimeLocation, TimeOutputLocation)
+	shanghai := time.FixedZone("CST", 8*60*60)
+	TimeLocation = shanghai
+	TimeOutputLocation = time.UTC
+
+	var ti Time
+	assert.NoError(t, json.Unmarshal([]byte(`"2023-10-27 18:00:00"`), &ti))
+	assert.True(t, testTime.Equal(ti.Time))
+	assert.Equal(t, shanghai, ti.Time.Location())
+
+	data, err := json.Marshal(ti)
+	assert.NoError(t, err)
+	assert.Equal(t, testTimeStr, string(data))
+	text, err := ti.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, testTimeText, string(text))
+
+	TimeOutputLocation = shanghai
+	v := url.Values{}
+	assert.NoError(t, TimeFrom(testTime).EncodeValues("t", &v))
+	assert.Equal(t, "2023-10-27 18:00:00", v.Get("t"))
+
+	// zoned input keeps its instant
+	assert.NoError(t, ti.UnmarshalText([]byte("2023-10-27T10:00:00Z")))
+	assert.True(t, testTime.Equal(ti.Time))
+}
+
+func TestTime_Precision(t *testing.T) {
+	defer func(p time.Duration, round bool) { TimePrecision, TimeRound = p, round }(TimePrecision, TimeRound)
+	precise := time.Date(2023, 10, 27, 10, 0, 0, 123456789, time.UTC)
+
+	// without a precision only the monotonic clock reading is stripped
+	now := time.Now()
+	assert.Equal(t, now.Round(0), TimeFrom(now).Time)
+
+	TimePrecision = time.Microsecond
+	assert.Equal(t, time.Date(2023, 10, 27, 10, 0, 0, 123456000, time.UTC), TimeFrom(precise).Time)
+	assert.Equal(t, time.Date(2023, 10, 27, 10, 0, 0, 123456000, time.UTC), NewTime(precise, true).Time)
+
+	TimeRound = true
+	assert.Equal(t, time.Date(2023, 10, 27, 10, 0, 0, 123457000, time.UTC), TimeFrom(precise).Time)
+
+	TimePrecision, TimeRound = time.Second, false
+	raw := Time{}
+	raw.Time, raw.Valid = precise, true // bypasses NewTime
+	v, err := raw.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, testTime, v)
+	data, err := json.Marshal(raw)
+	assert.NoError(t, err)
+	assert.Equal(t, testTimeStr, string(data))
+
+	// a round trip through a second precision column keeps the value equal
+	var scanned Time
+	assert.NoError(t, scanned.Scan(v))
+	assert.True(t, TimeFrom(precise).Equal(scanned))
+}
+
+func TestTime_PrecisionKeepsDatesAndBinary(t *testing.T) {
+	defer func(p time.Duration, round bool) { TimePrecision, TimeRound = p, round }(TimePrecision, TimeRound)
+	TimePrecision, TimeRound = time.Second, true
+	late := time.Date(2023, 1, 1, 23, 59, 59, 700000000, time.UTC)
+
+	var d Date
+	assert.NoError(t, d.Scan(late))
+	assert.Equal(t, DateFrom(2023, 1, 1), d)
+
+	raw := Time{}
+	raw.Time, raw.Valid = late, true // bypasses NewTime
+	assert.Equal(t, DateFrom(2023, 1, 1), DateFromTime(raw, nil))
+	assert.Equal(t, TimeOfDayFrom(23, 59, 59, 700000000), TimeOfDayFromTime(raw, nil))
+	assert.Equal(t, late, TimeOfDayFrom(23, 59, 59, 700000000).On(DateFrom(2023, 1, 1), time.UTC).Time)
+
+	// midnight in Shanghai is 16:00 UTC, which a day precision would truncate
+	TimePrecision = 24 * time.Hour
+	shanghai := time.FixedZone("CST", 8*60*60)
+	assert.Equal(t, time.Date(2023, 1, 1, 0, 0, 0, 0, shanghai), DateFrom(2023, 1, 1).TimeIn(shanghai).Time)
+	TimePrecision = time.Second
+
+	data, err := raw.MarshalBinary()
+	assert.NoError(t, err)
+	var got Time
+	assert.NoError(t, got.UnmarshalBinary(data))
+	assert.Equal(t, late, got.Time)
+
+	data, err = raw.GobEncode()
+	assert.NoError(t, err)
+	got = Time{}
+	assert.NoError(t, got.GobDecode(data))
+	assert.Equal(t, late, got.Time)
+}
+
+func TestTime_Equal(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*60*60)
+	assert.True(t, TimeFrom(testTime).Equal(TimeFrom(testTime.In(shanghai))))
+	assert.False(t, TimeFrom(testTime).Equal(TimeFrom(testTime.Add(time.Nanosecond))))
+	assert.False(t, TimeFrom(testTime).Equal(NewTime(time.Time{}, false)))
+	assert.True(t, NewTime(time.Time{}, false).Equal(NewTime(testTime, false)))
+}
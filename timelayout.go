@@ -0,0 +1,162 @@
+package nulled
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// LayoutProvider supplies the layout of a TimeLayout. Implement it on an
+// empty struct to use a layout of your own:
+//
+//	type partnerLayout struct{}
+//
+//	func (partnerLayout) Layout() string { return "02/01/2006 15:04" }
+//
+//	type Order struct {
+//		ShippedAt nulled.TimeLayout[partnerLayout] `json:"shipped_at"`
+//	}
+type LayoutProvider interface {
+	Layout() string
+}
+
+// LocationProvider can be implemented by a LayoutProvider to fix the
+// location of a TimeLayout: times are converted to it on output, and text
+// without zone information is interpreted in it, instead of using
+// TimeOutputLocation and TimeLocation. Embedding a predefined provider
+// keeps its layout:
+//
+//	var shanghai = time.FixedZone("CST", 8*60*60)
+//
+//	type shanghaiDateTime struct{ nulled.LayoutDateTime }
+//
+//	func (shanghaiDateTime) Location() *time.Location { return shanghai }
+type LocationProvider interface {
+	Location() *time.Location
+}
+
+// Predefined layout providers.
+type (
+	LayoutRFC3339     struct{} // time.RFC3339
+	LayoutRFC3339Nano struct{} // time.RFC3339Nano
+	LayoutDateTime    struct{} // time.DateTime, "2006-01-02 15:04:05"
+	LayoutDateOnly    struct{} // time.DateOnly, "2006-01-02"
+)
+
+func (LayoutRFC3339) Layout() string     { return time.RFC3339 }
+func (LayoutRFC3339Nano) Layout() string { return time.RFC3339Nano }
+func (LayoutDateTime) Layout() string    { return time.DateTime }
+func (LayoutDateOnly) Layout() string    { return time.DateOnly }
+
+// TimeLayout is a Time that is written and read with the layout of L in
+// MarshalJSON, UnmarshalJSON, MarshalText, UnmarshalText and EncodeValues,
+// instead of RFC 3339 and TimeLayouts. Text without zone information is
+// interpreted in TimeLocation and times are written in TimeOutputLocation,
+// unless L is also a LocationProvider. Other encodings behave like Time.
+//
+//	CreatedAt nulled.TimeLayout[nulled.LayoutDateTime] `json:"created_at" url:"created_at"`
+type TimeLayout[L LayoutProvider] struct {
+	Time
+}
+
+func (t TimeLayout[L]) layout() string {
+	var l L
+	return l.Layout()
+}
+
+// locations returns the location of zone-less input and of output.
+func (t TimeLayout[L]) locations() (in, out *time.Location) {
+	var l L
+	if p, ok := any(l).(LocationProvider); ok {
+		return p.Location(), p.Location()
+	}
+	return TimeLocation, TimeOutputLocation
+}
+
+// format returns the time formatted with the layout in the output location.
+func (t TimeLayout[L]) format() string {
+	_, out := t.locations()
+	return outputLocation(reducePrecision(t.Time.Time), out).Format(t.layout())
+}
+
+// EncodeValues implements the query.Encoder interface.
+func (t TimeLayout[L]) EncodeValues(key string, v *url.Values) error {
+	return t.encodeValues(key, v, DefaultPolicy)
+}
+
+func (t TimeLayout[L]) encodeValues(key string, v *url.Values, p Policy) error {
+	tt := t.Time.WithPolicy(p)
+	if !tt.Valid {
+		return nil
+	}
+	v.Set(key, t.format())
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t TimeLayout[L]) MarshalJSON() ([]byte, error) {
+	return t.marshalJSON(DefaultPolicy)
+}
+
+func (t TimeLayout[L]) marshalJSON(p Policy) ([]byte, error) {
+	tt := t.Time.WithPolicy(p)
+	if !tt.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.format())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (t *TimeLayout[L]) UnmarshalJSON(data []byte) error {
+	return t.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (t *TimeLayout[L]) unmarshalJSON(data []byte, p Policy) error {
+	if isJSONNull(data) {
+		t.Time = NewTime(time.Time{}, false)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Time = NewTime(time.Time{}, false)
+		return err
+	}
+	return t.unmarshalText([]byte(s), p)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (t TimeLayout[L]) MarshalText() ([]byte, error) {
+	return t.marshalText(DefaultPolicy)
+}
+
+func (t TimeLayout[L]) marshalText(p Policy) ([]byte, error) {
+	tt := t.Time.WithPolicy(p)
+	if !tt.Valid {
+		return []byte{}, nil
+	}
+	return []byte(t.format()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Empty text and "null" are null.
+func (t *TimeLayout[L]) UnmarshalText(text []byte) error {
+	return t.unmarshalText(text, DefaultPolicy)
+}
+
+func (t *TimeLayout[L]) unmarshalText(text []byte, p Policy) error {
+	s := p.text(string(text))
+	if s == "" || s == "null" {
+		t.Time = NewTime(time.Time{}, false)
+		return nil
+	}
+	in, _ := t.locations()
+	tt, err := time.ParseInLocation(t.layout(), s, in)
+	if err != nil {
+		t.Time = NewTime(time.Time{}, false)
+		return &ScanError{Src: s, Type: "TimeLayout", Err: strconv.ErrSyntax}
+	}
+	t.Time = NewTime(tt, true).WithPolicy(p)
+	return nil
+}
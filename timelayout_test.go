@@ -0,0 +1,106 @@
+package nulled
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dottedLayout struct{}
+
+func (dottedLayout) Layout() string { return "02.01.2006 15:04" }
+
+func TestTimeLayout_JSON(t *testing.T) {
+	type order struct {
+		Created TimeLayout[LayoutDateTime] `json:"created"`
+		Shipped TimeLayout[dottedLayout]   `json:"shipped"`
+		Due     TimeLayout[LayoutDateOnly] `json:"due"`
+	}
+	in := order{
+		Created: TimeLayout[LayoutDateTime]{TimeFrom(testTime)},
+		Shipped: TimeLayout[dottedLayout]{TimeFrom(time.Date(2023, 10, 28, 9, 30, 0, 0, time.UTC))},
+	}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"created":"2023-10-27 10:00:00","shipped":"28.10.2023 09:30","due":null}`, string(data))
+
+	var out order
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"created":"","due":"2023-10-27"}`), &out))
+	assert.False(t, out.Created.Valid)
+	assert.Equal(t, time.Date(2023, 10, 27, 0, 0, 0, 0, time.UTC), out.Due.Time.Time)
+
+	var scanErr *ScanError
+	assert.True(t, errors.As(json.Unmarshal([]byte(`{"created":"2023-10-27T10:00:00Z"}`), &out), &scanErr))
+	assert.False(t, out.Created.Valid)
+	var typeErr *json.UnmarshalTypeError
+	assert.True(t, errors.As(json.Unmarshal([]byte(`{"created":1698400800}`), &out), &typeErr))
+}
+
+func TestTimeLayout_Text(t *testing.T) {
+	ti := TimeLayout[dottedLayout]{TimeFrom(testTime)}
+	text, err := ti.MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "27.10.2023 10:00", string(text))
+
+	var out TimeLayout[dottedLayout]
+	require.NoError(t, out.UnmarshalText(text))
+	assert.Equal(t, ti, out)
+
+	text, err = TimeLayout[dottedLayout]{}.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	// form decoding goes through the unexported policy aware method
+	require.NoError(t, UnmarshalTextWithPolicy(&out, []byte(" 27.10.2023 10:00 "), DefaultPolicy))
+	assert.Equal(t, ti, out)
+	assert.Error(t, UnmarshalTextWithPolicy(&out, []byte("2023-10-27 10:00:00"), DefaultPolicy))
+}
+
+func TestTimeLayout_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, TimeLayout[LayoutRFC3339]{TimeFrom(testTime)}.EncodeValues("a", &v))
+	require.NoError(t, TimeLayout[LayoutDateOnly]{TimeFrom(testTime)}.EncodeValues("b", &v))
+	require.NoError(t, TimeLayout[LayoutDateOnly]{}.EncodeValues("c", &v))
+	assert.Equal(t, url.Values{"a": {"2023-10-27T10:00:00Z"}, "b": {"2023-10-27"}}, v)
+
+	var out TimeLayout[LayoutDateOnly]
+	require.NoError(t, out.UnmarshalText([]byte(v.Get("b"))))
+	assert.Equal(t, time.Date(2023, 10, 27, 0, 0, 0, 0, time.UTC), out.Time.Time)
+}
+
+var testShanghai = time.FixedZone("CST", 8*60*60)
+
+type shanghaiDateTime struct{ LayoutDateTime }
+
+func (shanghaiDateTime) Location() *time.Location { return testShanghai }
+
+func TestTimeLayout_LocationProvider(t *testing.T) {
+	defer func(out *time.Location) { TimeOutputLocation = out }(TimeOutputLocation)
+	TimeOutputLocation = time.UTC
+
+	ti := TimeLayout[shanghaiDateTime]{TimeFrom(testTime)}
+	data, err := json.Marshal(ti)
+	require.NoError(t, err)
+	assert.Equal(t, `"2023-10-27 18:00:00"`, string(data))
+	v := url.Values{}
+	require.NoError(t, ti.EncodeValues("t", &v))
+	assert.Equal(t, "2023-10-27 18:00:00", v.Get("t"))
+
+	var out TimeLayout[shanghaiDateTime]
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.True(t, testTime.Equal(out.Time.Time))
+	assert.Equal(t, testShanghai, out.Time.Time.Location())
+
+	// without a LocationProvider the global output location applies
+	data, err = json.Marshal(TimeLayout[LayoutDateTime]{TimeFrom(out.Time.Time)})
+	require.NoError(t, err)
+	assert.Equal(t, `"2023-10-27 10:00:00"`, string(data))
+}
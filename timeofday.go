@@ -0,0 +1,409 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
+)
+
+// TimeOfDay is a nullable wall clock time without date or zone, such as the
+// value of a SQL TIME column. Hour ranges from 0 to 23, except for the end of
+// day 24:00:00 that PostgreSQL allows, which has Hour 24 and is after every
+// other time of day.
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+	Valid      bool
+}
+
+const oneDay = 24 * time.Hour
+
+// NewTimeOfDay creates a TimeOfDay. Out of range values are normalized
+// around the clock, e.g. 23:60 becomes 00:00.
+func NewTimeOfDay(hour, minute, second, nanosecond int, valid bool) TimeOfDay {
+	if !valid {
+		return TimeOfDay{}
+	}
+	d := time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute +
+		time.Duration(second)*time.Second + time.Duration(nanosecond)
+	return timeOfDaySinceMidnight(d)
+}
+
+// TimeOfDayFrom creates a valid TimeOfDay.
+func TimeOfDayFrom(hour, minute, second, nanosecond int) TimeOfDay {
+	return NewTimeOfDay(hour, minute, second, nanosecond, true)
+}
+
+// TimeOfDayFromTime returns the clock of t in loc, or in the location t
+// carries when loc is nil. A null Time gives a null TimeOfDay.
+func TimeOfDayFromTime(t Time, loc *time.Location) TimeOfDay {
+	if t.isNull() {
+		return TimeOfDay{}
+	}
+	if loc != nil {
+		return timeOfDayOf(t.Time.In(loc))
+	}
+	return timeOfDayOf(t.Time)
+}
+
+// timeOfDayOf returns the clock of t in the location t carries.
+func timeOfDayOf(t time.Time) TimeOfDay {
+	h, m, s := t.Clock()
+	return TimeOfDay{Hour: h, Minute: m, Second: s, Nanosecond: t.Nanosecond(), Valid: true}
+}
+
+// timeOfDaySinceMidnight returns the time of day d after midnight, wrapping
+// around the clock.
+func timeOfDaySinceMidnight(d time.Duration) TimeOfDay {
+	d %= oneDay
+	if d < 0 {
+		d += oneDay
+	}
+	return TimeOfDay{
+		Hour:       int(d / time.Hour),
+		Minute:     int(d % time.Hour / time.Minute),
+		Second:     int(d % time.Minute / time.Second),
+		Nanosecond: int(d % time.Second),
+		Valid:      true,
+	}
+}
+
+// isEndOfDay reports whether t is 24:00:00.
+func (t TimeOfDay) isEndOfDay() bool {
+	return t.Hour == 24 && t.Minute == 0 && t.Second == 0 && t.Nanosecond == 0
+}
+
+// normalized returns t with every field in range, as NewTimeOfDay builds it,
+// for values written as struct literals. 24:00:00 is kept.
+func (t TimeOfDay) normalized() TimeOfDay {
+	if !t.Valid || t.isEndOfDay() {
+		return t
+	}
+	return timeOfDaySinceMidnight(t.sinceMidnight())
+}
+
+// sinceMidnight returns the time elapsed since midnight.
+func (t TimeOfDay) sinceMidnight() time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second + time.Duration(t.Nanosecond)
+}
+
+// On returns the time of day on date d in loc, or a null Time if either is
+// null. The time isn't reduced to TimePrecision, so the date and clock stay
+// as given.
+func (t TimeOfDay) On(d Date, loc *time.Location) Time {
+	if !t.Valid || !d.Valid {
+		return NewTime(time.Time{}, false)
+	}
+	return exactTime(time.Date(d.Year, d.Month, d.Day, t.Hour, t.Minute, t.Second, t.Nanosecond, loc), true)
+}
+
+// IsZero reports whether the value is null.
+func (t TimeOfDay) IsZero() bool {
+	return !t.Valid
+}
+
+// String returns the time as "15:04:05", followed by the fractional seconds
+// if there are any, or "" if it is null.
+func (t TimeOfDay) String() string {
+	if !t.Valid {
+		return ""
+	}
+	if t.isEndOfDay() {
+		return "24:00:00"
+	}
+	return time.Date(0, 1, 1, t.Hour, t.Minute, t.Second, t.Nanosecond, time.UTC).Format("15:04:05.999999999")
+}
+
+// Add returns the time of day d later, wrapping around midnight. A null value
+// stays null.
+func (t TimeOfDay) Add(d time.Duration) TimeOfDay {
+	if !t.Valid {
+		return TimeOfDay{}
+	}
+	return timeOfDaySinceMidnight(t.sinceMidnight() + d)
+}
+
+// Sub returns the duration t-u within the same day, negative if t is before
+// u. It returns 0 if either value is null.
+func (t TimeOfDay) Sub(u TimeOfDay) time.Duration {
+	if !t.Valid || !u.Valid {
+		return 0
+	}
+	return t.sinceMidnight() - u.sinceMidnight()
+}
+
+// Compare returns -1 if t is before u, +1 if t is after u and 0 if they are
+// equal. A null value is before every valid value.
+func (t TimeOfDay) Compare(u TimeOfDay) int {
+	switch {
+	case !t.Valid && !u.Valid:
+		return 0
+	case !t.Valid:
+		return -1
+	case !u.Valid:
+		return 1
+	}
+	switch d := t.Sub(u); {
+	case d < 0:
+		return -1
+	case d > 0:
+		return 1
+	}
+	return 0
+}
+
+// Before reports whether t is before u.
+func (t TimeOfDay) Before(u TimeOfDay) bool {
+	return t.Compare(u) < 0
+}
+
+// After reports whether t is after u.
+func (t TimeOfDay) After(u TimeOfDay) bool {
+	return t.Compare(u) > 0
+}
+
+// parseTimeOfDay parses "15:04", "15:04:05" and "15:04:05" followed by
+// fractional seconds, as well as the end of day "24:00:00". Blank text is
+// null.
+func parseTimeOfDay(s string) (TimeOfDay, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return TimeOfDay{}, nil
+	}
+	endOfDay := false
+	if rest, ok := strings.CutPrefix(s, "24:"); ok {
+		endOfDay, s = true, "00:"+rest
+	}
+	// time.Parse accepts fractional seconds after "05" even though the
+	// layout doesn't mention them
+	for _, layout := range []string{time.TimeOnly, "15:04"} {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		v := timeOfDayOf(t)
+		if endOfDay {
+			if v.sinceMidnight() != 0 {
+				return TimeOfDay{}, strconv.ErrSyntax
+			}
+			return TimeOfDay{Hour: 24, Valid: true}, nil
+		}
+		return v, nil
+	}
+	return TimeOfDay{}, strconv.ErrSyntax
+}
+
+func (t TimeOfDay) EncodeValues(key string, v *url.Values) error {
+	if !t.Valid {
+		return nil
+	}
+	v.Set(key, t.String())
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	if !t.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.String())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts null and strings understood by UnmarshalText.
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	if isJSONNull(data) {
+		*t = TimeOfDay{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		*t = TimeOfDay{}
+		return err
+	}
+	return t.UnmarshalText([]byte(s))
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Empty text and "null" are null.
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	return t.unmarshalText(text, DefaultPolicy)
+}
+
+func (t *TimeOfDay) unmarshalText(text []byte, p Policy) error {
+	s := p.text(string(text))
+	if s == "null" {
+		*t = TimeOfDay{}
+		return nil
+	}
+	v, err := parseTimeOfDay(s)
+	if err != nil {
+		*t = TimeOfDay{}
+		return &ScanError{Src: s, Type: "TimeOfDay", Err: err}
+	}
+	*t = v
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (t TimeOfDay) MarshalYAML() (any, error) {
+	return t.marshalYAML(DefaultPolicy)
+}
+
+func (t TimeOfDay) marshalYAML(Policy) (any, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return yamlScalar("!!str", t.String()), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// The value is parsed the same way as UnmarshalText.
+func (t *TimeOfDay) UnmarshalYAML(value *yaml.Node) error {
+	return t.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (t *TimeOfDay) unmarshalYAML(value *yaml.Node, p Policy) error {
+	s, valid, err := yamlText(value, "nulled.TimeOfDay")
+	if err != nil || !valid {
+		*t = TimeOfDay{}
+		return err
+	}
+	return t.unmarshalText([]byte(s), p)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (t TimeOfDay) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return marshalXMLElement(e, start, t.String(), t.Valid)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (t *TimeOfDay) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*t = TimeOfDay{}
+		return nil
+	}
+	return t.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (t TimeOfDay) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if !t.Valid {
+		return xml.Attr{}, nil
+	}
+	return xml.Attr{Name: name, Value: t.String()}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (t *TimeOfDay) UnmarshalXMLAttr(attr xml.Attr) error {
+	return t.UnmarshalText([]byte(attr.Value))
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts the text in string or []byte form that MySQL and PostgreSQL
+// drivers return for TIME columns, and time.Time, whose clock is taken in the
+// location it carries.
+func (t *TimeOfDay) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*t = TimeOfDay{}
+		return nil
+	case time.Time:
+		*t = timeOfDayOf(v)
+		return nil
+	}
+
+	s, ok := toText(value)
+	if !ok {
+		return &ScanError{Src: value, Type: "TimeOfDay", Err: ErrUnsupportedType}
+	}
+	v, err := parseTimeOfDay(s)
+	if err != nil {
+		return &ScanError{Src: value, Type: "TimeOfDay", Err: err}
+	}
+	*t = v
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// A valid value is written as "15:04:05" text, with fractional seconds if
+// there are any.
+func (t TimeOfDay) Value() (driver.Value, error) {
+	if !t.Valid {
+		return nil, nil
+	}
+	return t.String(), nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (t TimeOfDay) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(t.Valid)
+	if !t.Valid {
+		return b, nil
+	}
+	return binary.AppendUvarint(b, uint64(t.normalized().sinceMidnight())), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (t *TimeOfDay) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*t = TimeOfDay{}
+		return nil
+	}
+	n, size := binary.Uvarint(p)
+	if size <= 0 || size != len(p) || n > uint64(oneDay) {
+		return ErrInvalidBinary
+	}
+	if n == uint64(oneDay) {
+		*t = TimeOfDay{Hour: 24, Valid: true}
+		return nil
+	}
+	*t = timeOfDaySinceMidnight(time.Duration(n))
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (t TimeOfDay) GobEncode() ([]byte, error) {
+	return t.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (t *TimeOfDay) GobDecode(data []byte) error {
+	return t.UnmarshalBinary(data)
+}
+
+// NullValue returns the value as a null.String holding its text form, since
+// null.v4 has no time of day type.
+func (t TimeOfDay) NullValue() null.String {
+	if t.Valid {
+		return null.StringFrom(t.String())
+	}
+	return null.NewString("", false)
+}
@@ -0,0 +1,230 @@
+package nulled
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+	"gopkg.in/yaml.v3"
+)
+
+var testOpening = TimeOfDayFrom(9, 30, 0, 0)
+
+func TestTimeOfDay_New(t *testing.T) {
+	assert.Equal(t, TimeOfDay{Hour: 9, Minute: 30, Valid: true}, testOpening)
+	assert.Equal(t, TimeOfDayFrom(0, 0, 0, 0), TimeOfDayFrom(23, 60, 0, 0))
+	assert.Equal(t, TimeOfDay{}, NewTimeOfDay(9, 30, 0, 0, false))
+	assert.True(t, TimeOfDay{}.IsZero())
+	assert.False(t, TimeOfDayFrom(0, 0, 0, 0).IsZero())
+
+	assert.Equal(t, "09:30:00", testOpening.String())
+	assert.Equal(t, "23:59:59.5", TimeOfDayFrom(23, 59, 59, 500000000).String())
+	assert.Equal(t, "", TimeOfDay{}.String())
+}
+
+func TestTimeOfDay_Time(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*60*60)
+	ti := testOpening.On(testDate, shanghai)
+	assert.Equal(t, time.Date(2023, 10, 27, 9, 30, 0, 0, shanghai), ti.Time)
+	assert.Equal(t, testOpening, TimeOfDayFromTime(ti, nil))
+	assert.Equal(t, TimeOfDayFrom(1, 30, 0, 0), TimeOfDayFromTime(ti, time.UTC))
+	assert.False(t, testOpening.On(Date{}, time.UTC).Valid)
+	assert.False(t, TimeOfDayFromTime(NewTime(time.Time{}, false), nil).Valid)
+}
+
+func TestTimeOfDay_Arithmetic(t *testing.T) {
+	closing := TimeOfDayFrom(22, 0, 0, 0)
+	assert.Equal(t, 12*time.Hour+30*time.Minute, closing.Sub(testOpening))
+	assert.Equal(t, -(12*time.Hour + 30*time.Minute), testOpening.Sub(closing))
+	assert.Equal(t, time.Duration(0), closing.Sub(TimeOfDay{}))
+
+	assert.Equal(t, TimeOfDayFrom(1, 0, 0, 0), closing.Add(3*time.Hour))
+	assert.Equal(t, TimeOfDayFrom(23, 0, 0, 0), TimeOfDayFrom(1, 0, 0, 0).Add(-2*time.Hour))
+	assert.Equal(t, TimeOfDay{}, TimeOfDay{}.Add(time.Hour))
+
+	assert.True(t, testOpening.Before(closing))
+	assert.True(t, closing.After(testOpening))
+	assert.True(t, testOpening.After(TimeOfDay{}))
+	assert.Equal(t, 0, testOpening.Compare(TimeOfDayFrom(9, 30, 0, 0)))
+}
+
+func TestTimeOfDay_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    TimeOfDay
+		wantErr bool
+	}{
+		{text: "09:30", want: testOpening},
+		{text: "9:30", want: testOpening},
+		{text: "09:30:00", want: testOpening},
+		{text: " 23:59:59.123456 ", want: TimeOfDayFrom(23, 59, 59, 123456000)},
+		{text: "23:59:59,5", want: TimeOfDayFrom(23, 59, 59, 500000000)},
+		{text: "", want: TimeOfDay{}},
+		{text: "null", want: TimeOfDay{}},
+		{text: "24:00", want: TimeOfDay{Hour: 24, Valid: true}},
+		{text: "24:00:00.000", want: TimeOfDay{Hour: 24, Valid: true}},
+		{text: "24:00:01", wantErr: true},
+		{text: "25:00", wantErr: true},
+		{text: "09:61", wantErr: true},
+		{text: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var v TimeOfDay
+			err := v.UnmarshalText([]byte(tt.text))
+			if tt.wantErr {
+				var scanErr *ScanError
+				assert.ErrorAs(t, err, &scanErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestTimeOfDay_JSON(t *testing.T) {
+	type shop struct {
+		Opens  TimeOfDay `json:"opens"`
+		Closes TimeOfDay `json:"closes"`
+	}
+	in := shop{Opens: testOpening}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"opens":"09:30:00","closes":null}`, string(data))
+
+	var out shop
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+	require.NoError(t, json.Unmarshal([]byte(`{"opens":"","closes":"22:00"}`), &out))
+	assert.Equal(t, shop{Closes: TimeOfDayFrom(22, 0, 0, 0)}, out)
+	assert.Error(t, json.Unmarshal([]byte(`{"opens":930}`), &out))
+}
+
+func TestTimeOfDay_YAML(t *testing.T) {
+	type shift struct {
+		Start TimeOfDay `yaml:"start"`
+		End   TimeOfDay `yaml:"end"`
+	}
+	in := shift{Start: testOpening, End: TimeOfDay{Hour: 24, Valid: true}}
+	data, err := yaml.Marshal(in)
+	require.NoError(t, err)
+	assert.Equal(t, "start: 09:30:00\nend: 24:00:00\n", string(data))
+
+	var out shift
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	data, err = yaml.Marshal(shift{})
+	require.NoError(t, err)
+	assert.Equal(t, "start: null\nend: null\n", string(data))
+	assert.Error(t, yaml.Unmarshal([]byte("start: 25:00"), &out))
+	assert.Error(t, yaml.Unmarshal([]byte("start: {h: 9}"), &out))
+}
+
+func TestTimeOfDay_XML(t *testing.T) {
+	type shift struct {
+		XMLName xml.Name  `xml:"shift"`
+		Start   TimeOfDay `xml:"start,attr"`
+		End     TimeOfDay `xml:"end"`
+	}
+	data, err := xml.Marshal(shift{Start: testOpening})
+	require.NoError(t, err)
+	assert.Equal(t, `<shift start="09:30:00"><end xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></end></shift>`, string(data))
+
+	var out shift
+	require.NoError(t, xml.Unmarshal([]byte(`<shift start="09:30"><end>17:00:00.5</end></shift>`), &out))
+	assert.Equal(t, testOpening, out.Start)
+	assert.Equal(t, TimeOfDayFrom(17, 0, 0, 500000000), out.End)
+	assert.Error(t, xml.Unmarshal([]byte(`<shift><end>noon</end></shift>`), &out))
+}
+
+func TestTimeOfDay_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, testOpening.EncodeValues("a", &v))
+	require.NoError(t, TimeOfDay{}.EncodeValues("b", &v))
+	assert.Equal(t, url.Values{"a": {"09:30:00"}}, v)
+}
+
+func TestTimeOfDay_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    TimeOfDay
+		wantErr bool
+	}{
+		{name: "nil", src: nil, want: TimeOfDay{}},
+		{name: "bytes", src: []byte("09:30:00"), want: testOpening},
+		{name: "fraction", src: "09:30:00.250000", want: TimeOfDayFrom(9, 30, 0, 250000000)},
+		{name: "end of day", src: []byte("24:00:00"), want: TimeOfDay{Hour: 24, Valid: true}},
+		{name: "time", src: time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC), want: testOpening},
+		{name: "invalid", src: "later", wantErr: true},
+		{name: "unsupported", src: int64(930), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v TimeOfDay
+			err := v.Scan(tt.src)
+			if tt.wantErr {
+				var scanErr *ScanError
+				assert.ErrorAs(t, err, &scanErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, v)
+		})
+	}
+}
+
+func TestTimeOfDay_SQL(t *testing.T) {
+	db := openTestDB(t)
+	_, err := db.Exec("INSERT", TimeOfDayFrom(9, 30, 0, 1000), TimeOfDay{})
+	require.NoError(t, err)
+	var a, b TimeOfDay
+	require.NoError(t, db.QueryRow("SELECT").Scan(&a, &b))
+	assert.Equal(t, TimeOfDayFrom(9, 30, 0, 1000), a)
+	assert.Equal(t, TimeOfDay{}, b)
+}
+
+func TestTimeOfDay_EndOfDay(t *testing.T) {
+	end := TimeOfDay{Hour: 24, Valid: true}
+	assert.Equal(t, "24:00:00", end.String())
+	assert.True(t, end.After(TimeOfDayFrom(23, 59, 59, 999999999)))
+	assert.Equal(t, TimeOfDayFrom(0, 30, 0, 0), end.Add(30*time.Minute))
+
+	v, err := end.Value()
+	require.NoError(t, err)
+	assert.Equal(t, "24:00:00", v)
+
+	data, err := end.MarshalBinary()
+	require.NoError(t, err)
+	var got TimeOfDay
+	require.NoError(t, got.UnmarshalBinary(data))
+	assert.Equal(t, end, got)
+}
+
+func TestTimeOfDay_BinaryNormalizes(t *testing.T) {
+	for _, in := range []TimeOfDay{
+		{Hour: 25, Valid: true},
+		{Hour: 23, Minute: 60, Valid: true},
+		{Hour: -1, Valid: true},
+		{Second: 90, Valid: true},
+	} {
+		data, err := in.MarshalBinary()
+		require.NoError(t, err)
+		var got TimeOfDay
+		require.NoError(t, got.UnmarshalBinary(data), "%+v", in)
+		assert.Equal(t, in.normalized(), got)
+	}
+	assert.Equal(t, TimeOfDayFrom(1, 0, 0, 0), TimeOfDay{Hour: 25, Valid: true}.normalized())
+}
+
+func TestTimeOfDay_NullValue(t *testing.T) {
+	assert.Equal(t, null.StringFrom("09:30:00"), testOpening.NullValue())
+	assert.Equal(t, null.NewString("", false), TimeOfDay{}.NullValue())
+}
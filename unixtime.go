@@ -0,0 +1,222 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnixPrecision supplies the unit of a UnixTimestamp. The unit must divide
+// one second.
+type UnixPrecision interface {
+	Unit() time.Duration
+}
+
+// Predefined Unix timestamp precisions.
+type (
+	UnixSeconds      struct{}
+	UnixMilliseconds struct{}
+	UnixMicroseconds struct{}
+	UnixNanoseconds  struct{}
+)
+
+func (UnixSeconds) Unit() time.Duration      { return time.Second }
+func (UnixMilliseconds) Unit() time.Duration { return time.Millisecond }
+func (UnixMicroseconds) Unit() time.Duration { return time.Microsecond }
+func (UnixNanoseconds) Unit() time.Duration  { return time.Nanosecond }
+
+// UnixTimestamp is a Time that is encoded as an integer count of P units
+// since the Unix epoch: a JSON number, numeric text in MarshalText and
+// EncodeValues, and an int64 for integer SQL columns. A null value is JSON
+// null; use `omitzero` (Go 1.24+) to leave it out. Decoding also accepts
+// numeric strings and fractional numbers, and decoded times are in
+// TimeLocation. Other encodings behave like Time.
+type UnixTimestamp[P UnixPrecision] struct {
+	Time
+}
+
+type (
+	UnixTime      = UnixTimestamp[UnixSeconds]
+	UnixMilliTime = UnixTimestamp[UnixMilliseconds]
+)
+
+func (t UnixTimestamp[P]) unit() time.Duration {
+	var p P
+	return p.Unit()
+}
+
+// Int64 returns the timestamp in P units, rounded down, or 0 if it is null.
+func (t UnixTimestamp[P]) Int64() int64 {
+	tt := t.Time.WithPolicy(DefaultPolicy)
+	if !tt.Valid {
+		return 0
+	}
+	perSecond := int64(time.Second / t.unit())
+	sec, nsec := tt.Time.Unix(), int64(tt.Time.Nanosecond())
+	return sec*perSecond + nsec/int64(t.unit())
+}
+
+// parse parses a possibly fractional number of P units.
+func (t UnixTimestamp[P]) parse(s string) (time.Time, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	n, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		if ne, ok := err.(*strconv.NumError); ok {
+			err = ne.Err
+		}
+		return time.Time{}, err
+	}
+	tt := t.fromUnits(n)
+	if frac != "" {
+		// the fraction of one unit, in nanoseconds
+		digits := frac + strings.Repeat("0", 9)
+		f, err := strconv.ParseUint(digits[:9], 10, 64)
+		if err != nil || strings.TrimLeft(frac, "0123456789") != "" {
+			return time.Time{}, strconv.ErrSyntax
+		}
+		fnsec := int64(f) * int64(t.unit()) / int64(time.Second)
+		if strings.HasPrefix(whole, "-") {
+			fnsec = -fnsec
+		}
+		tt = tt.Add(time.Duration(fnsec))
+	}
+	return tt, nil
+}
+
+// fromUnits returns the time n P units after the Unix epoch, in
+// TimeLocation.
+func (t UnixTimestamp[P]) fromUnits(n int64) time.Time {
+	perSecond := int64(time.Second / t.unit())
+	return time.Unix(n/perSecond, n%perSecond*int64(t.unit())).In(TimeLocation)
+}
+
+func (t UnixTimestamp[P]) EncodeValues(key string, v *url.Values) error {
+	return t.encodeValues(key, v, DefaultPolicy)
+}
+
+func (t UnixTimestamp[P]) encodeValues(key string, v *url.Values, p Policy) error {
+	if !t.Time.WithPolicy(p).Valid {
+		return nil
+	}
+	v.Set(key, strconv.FormatInt(t.Int64(), 10))
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (t UnixTimestamp[P]) MarshalJSON() ([]byte, error) {
+	return t.marshalJSON(DefaultPolicy)
+}
+
+func (t UnixTimestamp[P]) marshalJSON(p Policy) ([]byte, error) {
+	if !t.Time.WithPolicy(p).Valid {
+		return []byte("null"), nil
+	}
+	return strconv.AppendInt(nil, t.Int64(), 10), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts null, numbers and numeric strings; "" is null.
+func (t *UnixTimestamp[P]) UnmarshalJSON(data []byte) error {
+	return t.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (t *UnixTimestamp[P]) unmarshalJSON(data []byte, p Policy) error {
+	v, err := decodeLenientJSON(data, "UnixTimestamp")
+	if err != nil {
+		t.Time = NewTime(time.Time{}, false)
+		return err
+	}
+	switch s := v.(type) {
+	case nil:
+		t.Time = NewTime(time.Time{}, false)
+	case string:
+		return t.unmarshalText([]byte(s), p)
+	case json.Number:
+		return t.unmarshalText([]byte(s), p)
+	default:
+		t.Time = NewTime(time.Time{}, false)
+		return &ScanError{Src: s, Type: "UnixTimestamp", Err: ErrUnsupportedType}
+	}
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (t UnixTimestamp[P]) MarshalText() ([]byte, error) {
+	return t.marshalText(DefaultPolicy)
+}
+
+func (t UnixTimestamp[P]) marshalText(p Policy) ([]byte, error) {
+	if !t.Time.WithPolicy(p).Valid {
+		return []byte{}, nil
+	}
+	return strconv.AppendInt(nil, t.Int64(), 10), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// Empty text and "null" are null.
+func (t *UnixTimestamp[P]) UnmarshalText(text []byte) error {
+	return t.unmarshalText(text, DefaultPolicy)
+}
+
+func (t *UnixTimestamp[P]) unmarshalText(text []byte, p Policy) error {
+	s := p.text(string(text))
+	if s == "" || s == "null" {
+		t.Time = NewTime(time.Time{}, false)
+		return nil
+	}
+	tt, err := t.parse(s)
+	if err != nil {
+		t.Time = NewTime(time.Time{}, false)
+		return &ScanError{Src: s, Type: "UnixTimestamp", Err: err}
+	}
+	t.Time = NewTime(tt, true)
+	return nil
+}
+
+// Scan implements the sql.Scanner interface.
+// It accepts integers and integer text in P units, and time.Time for
+// columns that are not integers after all.
+func (t *UnixTimestamp[P]) Scan(value any) error {
+	return t.scan(value, DefaultPolicy)
+}
+
+func (t *UnixTimestamp[P]) scan(value any, p Policy) error {
+	switch v := value.(type) {
+	case nil:
+		t.Time = NewTime(time.Time{}, false)
+		return nil
+	case time.Time:
+		t.Time = NewTime(v, true).WithPolicy(p)
+		return nil
+	}
+	n, valid, err := toInt64(value)
+	if err != nil {
+		if scanErr, ok := err.(*ScanError); ok {
+			scanErr.Type = "UnixTimestamp"
+		}
+		return err
+	}
+	if !valid {
+		t.Time = NewTime(time.Time{}, false)
+		return nil
+	}
+	t.Time = NewTime(t.fromUnits(n), true)
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// A valid time is written as an int64 in P units.
+func (t UnixTimestamp[P]) Value() (driver.Value, error) {
+	return t.value(DefaultPolicy)
+}
+
+func (t UnixTimestamp[P]) value(p Policy) (driver.Value, error) {
+	if !t.Time.WithPolicy(p).Valid {
+		return nil, nil
+	}
+	return t.Int64(), nil
+}
@@ -0,0 +1,94 @@
+package nulled
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnixTimestamp_JSON(t *testing.T) {
+	type payment struct {
+		PaidAt    UnixTime      `json:"paid_at"`
+		CreatedAt UnixMilliTime `json:"created_at"`
+		Refunded  UnixTime      `json:"refunded_at"`
+	}
+	in := payment{
+		PaidAt:    UnixTime{TimeFrom(testTime)},
+		CreatedAt: UnixMilliTime{TimeFrom(testTime.Add(250 * time.Millisecond))},
+	}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"paid_at":1698400800,"created_at":1698400800250,"refunded_at":null}`, string(data))
+
+	var out payment
+	require.NoError(t, json.Unmarshal(data, &out))
+	assert.Equal(t, in, out)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"paid_at":"1698400800","created_at":"","refunded_at":1698400800.5}`), &out))
+	assert.Equal(t, testTime, out.PaidAt.Time.Time)
+	assert.False(t, out.CreatedAt.Valid)
+	assert.Equal(t, testTime.Add(500*time.Millisecond), out.Refunded.Time.Time)
+
+	var scanErr *ScanError
+	assert.ErrorAs(t, json.Unmarshal([]byte(`{"paid_at":"2023-10-27"}`), &out), &scanErr)
+	assert.ErrorAs(t, json.Unmarshal([]byte(`{"paid_at":true}`), &out), &scanErr)
+}
+
+func TestUnixTimestamp_Precision(t *testing.T) {
+	ti := TimeFrom(time.Date(1969, 12, 31, 23, 59, 59, 999999999, time.UTC))
+	assert.Equal(t, int64(-1), UnixTime{ti}.Int64())
+	assert.Equal(t, int64(-1), UnixMilliTime{ti}.Int64())
+	assert.Equal(t, int64(-1), UnixTimestamp[UnixMicroseconds]{ti}.Int64())
+	assert.Equal(t, int64(-1), UnixTimestamp[UnixNanoseconds]{ti}.Int64())
+	assert.Equal(t, int64(0), UnixTime{}.Int64())
+
+	var u UnixTime
+	require.NoError(t, u.UnmarshalText([]byte("-1.5")))
+	assert.Equal(t, time.Unix(-2, 500000000).UTC(), u.Time.Time)
+
+	var m UnixMilliTime
+	require.NoError(t, m.UnmarshalText([]byte("1698400800000.5")))
+	assert.Equal(t, testTime.Add(500*time.Microsecond), m.Time.Time)
+
+	assert.ErrorIs(t, m.UnmarshalText([]byte("1.5e3")), strconv.ErrSyntax)
+	assert.ErrorIs(t, m.UnmarshalText([]byte("99999999999999999999")), strconv.ErrRange)
+}
+
+func TestUnixTimestamp_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, UnixTime{TimeFrom(testTime)}.EncodeValues("a", &v))
+	require.NoError(t, UnixMilliTime{TimeFrom(testTime)}.EncodeValues("b", &v))
+	require.NoError(t, UnixTime{}.EncodeValues("c", &v))
+	assert.Equal(t, url.Values{"a": {"1698400800"}, "b": {"1698400800000"}}, v)
+
+	text, err := UnixTime{}.MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+}
+
+func TestUnixTimestamp_SQL(t *testing.T) {
+	v, err := UnixMilliTime{TimeFrom(testTime)}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1698400800000), v)
+
+	db := openTestDB(t)
+	_, err = db.Exec("INSERT", UnixTime{TimeFrom(testTime)}, UnixTime{})
+	require.NoError(t, err)
+	var a, b UnixTime
+	require.NoError(t, db.QueryRow("SELECT").Scan(&a, &b))
+	assert.Equal(t, testTime, a.Time.Time)
+	assert.False(t, b.Valid)
+
+	require.NoError(t, a.Scan([]byte("1698400800")))
+	assert.Equal(t, testTime, a.Time.Time)
+	require.NoError(t, a.Scan(testTime))
+	assert.Equal(t, testTime, a.Time.Time)
+	var scanErr *ScanError
+	require.ErrorAs(t, a.Scan("soon"), &scanErr)
+	assert.Equal(t, "UnixTimestamp", scanErr.Type)
+}
@@ -0,0 +1,326 @@
+package nulled
+
+import (
+	"database/sql/driver"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Value is a nullable T. It implements JSON, text, YAML, XML, binary, gob,
+// SQL and url.Values encoding for any element type, delegating the element itself
+// to the Codec registered for T, or to DefaultCodec[T] if there is none:
+//
+//	type OrderID string
+//
+//	type Order struct {
+//		ID     nulled.Value[OrderID] `json:"id"`
+//		Amount nulled.Value[int32]   `json:"amount"`
+//	}
+//
+// String, Int, Float, Bool and Time are built on Value[string],
+// Value[int64], Value[float64], Value[bool] and Value[time.Time], so those
+// behave exactly like them. Like them, Value honors DefaultPolicy in every
+// encoding except binary and gob.
+type Value[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewValue creates a Value without applying any policy.
+func NewValue[T any](v T, valid bool) Value[T] {
+	return Value[T]{V: v, Valid: valid}
+}
+
+// ValueFrom creates a Value from v under DefaultPolicy.
+func ValueFrom[T any](v T) Value[T] {
+	return NewValue(v, true).WithPolicy(DefaultPolicy)
+}
+
+// ValueFromPtr creates a Value from the value v points to, or a null Value
+// if v is nil.
+func ValueFromPtr[T any](v *T) Value[T] {
+	if v == nil {
+		return Value[T]{}
+	}
+	return ValueFrom(*v)
+}
+
+func (v Value[T]) ValueOrZero() T {
+	if !v.Valid {
+		var zero T
+		return zero
+	}
+	return v.V
+}
+
+// Ptr returns a pointer to the value, or nil if it is null.
+func (v Value[T]) Ptr() *T {
+	if !v.Valid {
+		return nil
+	}
+	return &v.V
+}
+
+// IsZero reports whether the value is null.
+func (v Value[T]) IsZero() bool {
+	return !v.Valid
+}
+
+// WithPolicy returns the value cleaned up and nulled according to p by the
+// codec of T.
+func (v Value[T]) WithPolicy(p Policy) Value[T] {
+	if !v.Valid {
+		return Value[T]{}
+	}
+	x, valid := codecFor[T]().Normalize(v.V, p)
+	return NewValue(x, valid)
+}
+
+func (v *Value[T]) applyPolicy(p Policy) {
+	*v = v.WithPolicy(p)
+}
+
+func (v Value[T]) EncodeValues(key string, values *url.Values) error {
+	return v.encodeValues(key, values, DefaultPolicy)
+}
+
+func (v Value[T]) encodeValues(key string, values *url.Values, p Policy) error {
+	v = v.WithPolicy(p)
+	if !v.Valid {
+		return nil
+	}
+	s, err := codecFor[T]().EncodeValue(v.V)
+	if err != nil {
+		return err
+	}
+	values.Set(key, s)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v Value[T]) MarshalJSON() ([]byte, error) {
+	return v.marshalJSON(DefaultPolicy)
+}
+
+func (v Value[T]) marshalJSON(p Policy) ([]byte, error) {
+	v = v.WithPolicy(p)
+	if !v.Valid {
+		return []byte("null"), nil
+	}
+	return codecFor[T]().EncodeJSON(v.V)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// On error the value is set to null.
+func (v *Value[T]) UnmarshalJSON(data []byte) error {
+	return v.unmarshalJSON(data, DefaultPolicy)
+}
+
+func (v *Value[T]) unmarshalJSON(data []byte, p Policy) error {
+	if isJSONNull(data) {
+		*v = Value[T]{}
+		return nil
+	}
+	x, valid, err := codecFor[T]().DecodeJSON(data)
+	if err != nil {
+		*v = Value[T]{}
+		return err
+	}
+	*v = NewValue(x, valid).WithPolicy(p)
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+// A null value is encoded as empty text.
+func (v Value[T]) MarshalText() ([]byte, error) {
+	return v.marshalText(DefaultPolicy)
+}
+
+func (v Value[T]) marshalText(p Policy) ([]byte, error) {
+	v = v.WithPolicy(p)
+	if !v.Valid {
+		return []byte{}, nil
+	}
+	return codecFor[T]().EncodeText(v.V)
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+// On error the value is set to null.
+func (v *Value[T]) UnmarshalText(text []byte) error {
+	return v.unmarshalText(text, DefaultPolicy)
+}
+
+func (v *Value[T]) unmarshalText(text []byte, p Policy) error {
+	x, valid, err := codecFor[T]().DecodeText(p.text(string(text)))
+	if err != nil {
+		*v = Value[T]{}
+		return err
+	}
+	*v = NewValue(x, valid).WithPolicy(p)
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+// Numbers and bools are written as such, other values in their text form.
+func (v Value[T]) MarshalYAML() (any, error) {
+	return v.marshalYAML(DefaultPolicy)
+}
+
+func (v Value[T]) marshalYAML(p Policy) (any, error) {
+	v = v.WithPolicy(p)
+	if !v.Valid {
+		return nil, nil
+	}
+	text, err := codecFor[T]().EncodeText(v.V)
+	if err != nil {
+		return nil, err
+	}
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return yamlNumber(string(text)), nil
+	case reflect.Bool:
+		return yamlScalar("!!bool", string(text)), nil
+	}
+	if _, ok := any(v.V).(time.Time); ok {
+		return yamlScalar("!!timestamp", string(text)), nil
+	}
+	return yamlScalar("!!str", string(text)), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface.
+// Scalars are decoded like UnmarshalText.
+func (v *Value[T]) UnmarshalYAML(value *yaml.Node) error {
+	return v.unmarshalYAML(value, DefaultPolicy)
+}
+
+func (v *Value[T]) unmarshalYAML(value *yaml.Node, p Policy) error {
+	s, valid, err := yamlText(value, fmt.Sprintf("nulled.Value[%s]", reflect.TypeFor[T]()))
+	if err != nil || !valid {
+		*v = Value[T]{}
+		return err
+	}
+	return v.unmarshalText([]byte(s), p)
+}
+
+// MarshalXML implements the xml.Marshaler interface.
+func (v Value[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	v = v.WithPolicy(DefaultPolicy)
+	if !v.Valid {
+		return marshalXMLElement(e, start, "", false)
+	}
+	text, err := codecFor[T]().EncodeText(v.V)
+	if err != nil {
+		return err
+	}
+	return marshalXMLElement(e, start, string(text), true)
+}
+
+// UnmarshalXML implements the xml.Unmarshaler interface.
+// Empty elements and elements with xsi:nil="true" are considered null.
+func (v *Value[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	s, valid, err := unmarshalXMLElement(d, start)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*v = Value[T]{}
+		return nil
+	}
+	return v.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr implements the xml.MarshalerAttr interface.
+// A null value omits the attribute.
+func (v Value[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	v = v.WithPolicy(DefaultPolicy)
+	if !v.Valid {
+		return xml.Attr{}, nil
+	}
+	text, err := codecFor[T]().EncodeText(v.V)
+	if err != nil {
+		return xml.Attr{}, err
+	}
+	return xml.Attr{Name: name, Value: string(text)}, nil
+}
+
+// UnmarshalXMLAttr implements the xml.UnmarshalerAttr interface.
+func (v *Value[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	return v.UnmarshalText([]byte(attr.Value))
+}
+
+// Scan implements the sql.Scanner interface.
+// On error the value is left unchanged.
+func (v *Value[T]) Scan(src any) error {
+	return v.scan(src, DefaultPolicy)
+}
+
+func (v *Value[T]) scan(src any, p Policy) error {
+	if src == nil {
+		*v = Value[T]{}
+		return nil
+	}
+	x, valid, err := codecFor[T]().Scan(src)
+	if err != nil {
+		return err
+	}
+	*v = NewValue(x, valid).WithPolicy(p)
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+// Values that are null under DefaultPolicy are written as NULL.
+func (v Value[T]) Value() (driver.Value, error) {
+	return v.value(DefaultPolicy)
+}
+
+func (v Value[T]) value(p Policy) (driver.Value, error) {
+	v = v.WithPolicy(p)
+	if !v.Valid {
+		return nil, nil
+	}
+	return codecFor[T]().Value(v.V)
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (v Value[T]) MarshalBinary() ([]byte, error) {
+	b := binaryHeader(v.Valid)
+	if !v.Valid {
+		return b, nil
+	}
+	return codecFor[T]().EncodeBinary(b, v.V)
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (v *Value[T]) UnmarshalBinary(data []byte) error {
+	p, valid, err := readBinaryHeader(data)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		*v = Value[T]{}
+		return nil
+	}
+	x, err := codecFor[T]().DecodeBinary(p)
+	if err != nil {
+		return err
+	}
+	*v = NewValue(x, true)
+	return nil
+}
+
+// GobEncode implements the gob.GobEncoder interface.
+func (v Value[T]) GobEncode() ([]byte, error) {
+	return v.MarshalBinary()
+}
+
+// GobDecode implements the gob.GobDecoder interface.
+func (v *Value[T]) GobDecode(data []byte) error {
+	return v.UnmarshalBinary(data)
+}
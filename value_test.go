@@ -0,0 +1,365 @@
+package nulled
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderID string
+
+type level int32
+
+type point struct{ X, Y int }
+
+// sku implements encoding.TextMarshaler and encoding.TextUnmarshaler.
+type sku struct{ Vendor, Code string }
+
+func (s sku) MarshalText() ([]byte, error) {
+	return []byte(s.Vendor + "-" + s.Code), nil
+}
+
+func (s *sku) UnmarshalText(text []byte) error {
+	vendor, code, ok := strings.Cut(string(text), "-")
+	if !ok {
+		return errors.New("sku: missing vendor")
+	}
+	*s = sku{Vendor: vendor, Code: code}
+	return nil
+}
+
+// cents is an amount of money with a registered codec that writes it in
+// units.
+type cents int64
+
+type centsCodec struct{ DefaultCodec[cents] }
+
+func (centsCodec) EncodeText(c cents) ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%02d", c/100, c%100)), nil
+}
+
+func (centsCodec) DecodeText(s string) (cents, bool, error) {
+	if s == "" {
+		return 0, false, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, err
+	}
+	return cents(f*100 + 0.5), true, nil
+}
+
+func (c centsCodec) EncodeValue(v cents) (string, error) {
+	text, err := c.EncodeText(v)
+	return string(text), err
+}
+
+func init() {
+	RegisterCodec[cents](centsCodec{})
+}
+
+func TestValue_Constructors(t *testing.T) {
+	assert.Equal(t, Value[orderID]{V: " A1 ", Valid: true}, NewValue[orderID](" A1 ", true))
+	assert.Equal(t, Value[orderID]{V: "A1", Valid: true}, ValueFrom[orderID](" A1 "))
+	assert.False(t, ValueFrom[orderID]("  ").Valid)
+	assert.Equal(t, Value[level]{V: 0, Valid: true}, ValueFrom[level](0))
+
+	l := level(3)
+	assert.Equal(t, ValueFrom(l), ValueFromPtr(&l))
+	assert.False(t, ValueFromPtr[level](nil).Valid)
+
+	assert.Equal(t, level(3), ValueFrom(l).ValueOrZero())
+	assert.Equal(t, level(0), NewValue(l, false).ValueOrZero())
+	assert.Equal(t, &l, ValueFrom(l).Ptr())
+	assert.Nil(t, NewValue(l, false).Ptr())
+	assert.True(t, NewValue(l, false).IsZero())
+}
+
+func TestValue_WithPolicy(t *testing.T) {
+	zero := Policy{ZeroAsNull: true}
+	assert.False(t, ValueFrom[level](0).WithPolicy(zero).Valid)
+	assert.True(t, ValueFrom[level](1).WithPolicy(zero).Valid)
+	assert.False(t, ValueFrom(0.0).WithPolicy(zero).Valid)
+	assert.True(t, ValueFrom(false).WithPolicy(zero).Valid)
+	assert.Equal(t, NewValue[orderID](" a ", true), NewValue[orderID](" a ", true).WithPolicy(Policy{}))
+}
+
+func TestValue_JSON(t *testing.T) {
+	type order struct {
+		ID     Value[orderID] `json:"id"`
+		Level  Value[level]   `json:"level"`
+		At     Value[point]   `json:"at"`
+		SKU    Value[sku]     `json:"sku"`
+		Amount Value[cents]   `json:"amount"`
+	}
+
+	o := order{
+		ID:     ValueFrom[orderID]("A1"),
+		Level:  ValueFrom[level](2),
+		At:     ValueFrom(point{1, 2}),
+		SKU:    ValueFrom(sku{"acme", "x1"}),
+		Amount: ValueFrom[cents](150),
+	}
+	data, err := json.Marshal(o)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"A1","level":2,"at":{"X":1,"Y":2},"sku":"acme-x1","amount":150}`, string(data))
+
+	var got order
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, o, got)
+
+	data, err = json.Marshal(order{ID: ValueFrom[orderID](" ")})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":null,"level":null,"at":null,"sku":null,"amount":null}`, string(data))
+
+	got = order{Level: ValueFrom[level](2)}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":" B2 ","level":null}`), &got))
+	assert.Equal(t, order{ID: ValueFrom[orderID]("B2")}, got)
+
+	got.Level = ValueFrom[level](2)
+	assert.Error(t, json.Unmarshal([]byte(`{"level":"x"}`), &got))
+	assert.False(t, got.Level.Valid)
+	assert.Error(t, json.Unmarshal([]byte(`{"level":3000000000}`), &got))
+}
+
+func TestValue_Text(t *testing.T) {
+	text, err := ValueFrom[level](-7).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "-7", string(text))
+
+	text, err = ValueFrom(sku{"acme", "x1"}).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "acme-x1", string(text))
+
+	text, err = ValueFrom[cents](1205).MarshalText()
+	require.NoError(t, err)
+	assert.Equal(t, "12.05", string(text))
+
+	text, err = NewValue[level](0, false).MarshalText()
+	require.NoError(t, err)
+	assert.Empty(t, text)
+
+	var l Value[level]
+	require.NoError(t, l.UnmarshalText([]byte(" 12 ")))
+	assert.Equal(t, ValueFrom[level](12), l)
+	require.NoError(t, l.UnmarshalText([]byte("null")))
+	assert.False(t, l.Valid)
+	require.NoError(t, l.UnmarshalText([]byte(" ")))
+	assert.False(t, l.Valid)
+
+	err = l.UnmarshalText([]byte("3000000000"))
+	assert.ErrorIs(t, err, strconv.ErrRange)
+	var scanErr *ScanError
+	require.ErrorAs(t, err, &scanErr)
+	assert.Equal(t, "nulled.level", scanErr.Type)
+	assert.ErrorIs(t, l.UnmarshalText([]byte("x")), strconv.ErrSyntax)
+
+	var u Value[uint8]
+	require.NoError(t, u.UnmarshalText([]byte("255")))
+	assert.Equal(t, ValueFrom[uint8](255), u)
+	assert.ErrorIs(t, u.UnmarshalText([]byte("-1")), strconv.ErrSyntax)
+
+	var b Value[bool]
+	require.NoError(t, b.UnmarshalText([]byte("1")))
+	assert.Equal(t, ValueFrom(true), b)
+
+	var s Value[sku]
+	require.NoError(t, s.UnmarshalText([]byte("acme-x1")))
+	assert.Equal(t, ValueFrom(sku{"acme", "x1"}), s)
+	assert.Error(t, s.UnmarshalText([]byte("x1")))
+	assert.False(t, s.Valid)
+
+	var c Value[cents]
+	require.NoError(t, c.UnmarshalText([]byte("12.05")))
+	assert.Equal(t, ValueFrom[cents](1205), c)
+
+	var p Value[point]
+	assert.ErrorIs(t, p.UnmarshalText([]byte("1,2")), ErrUnsupportedType)
+}
+
+func TestValue_UnmarshalTextWithPolicy(t *testing.T) {
+	var id Value[orderID]
+	require.NoError(t, UnmarshalTextWithPolicy(&id, []byte(" a "), Policy{}))
+	assert.Equal(t, NewValue[orderID](" a ", true), id)
+
+	var l Value[level]
+	require.NoError(t, UnmarshalTextWithPolicy(&l, []byte("0"), Policy{ZeroAsNull: true}))
+	assert.False(t, l.Valid)
+}
+
+func TestValue_EncodeValues(t *testing.T) {
+	v := url.Values{}
+	require.NoError(t, ValueFrom[orderID]("A1").EncodeValues("id", &v))
+	require.NoError(t, ValueFrom[cents](150).EncodeValues("amount", &v))
+	require.NoError(t, ValueFrom(false).EncodeValues("paid", &v))
+	require.NoError(t, NewValue[level](0, false).EncodeValues("level", &v))
+	assert.Equal(t, url.Values{"id": {"A1"}, "amount": {"1.50"}, "paid": {"0"}}, v)
+}
+
+func TestValue_Scan(t *testing.T) {
+	var l Value[level]
+	require.NoError(t, l.Scan(int64(5)))
+	assert.Equal(t, ValueFrom[level](5), l)
+	require.NoError(t, l.Scan([]byte("6")))
+	assert.Equal(t, ValueFrom[level](6), l)
+	require.NoError(t, l.Scan(nil))
+	assert.False(t, l.Valid)
+
+	l = ValueFrom[level](1)
+	assert.ErrorIs(t, l.Scan(int64(1)<<40), strconv.ErrRange)
+	assert.Equal(t, ValueFrom[level](1), l, "unchanged on error")
+	assert.ErrorIs(t, l.Scan(time.Now()), ErrUnsupportedType)
+
+	var u Value[uint16]
+	require.NoError(t, u.Scan(int64(65535)))
+	assert.Equal(t, ValueFrom[uint16](65535), u)
+	assert.ErrorIs(t, u.Scan(int64(-1)), strconv.ErrRange)
+
+	var id Value[orderID]
+	require.NoError(t, id.Scan([]byte(" A1 ")))
+	assert.Equal(t, ValueFrom[orderID]("A1"), id)
+
+	var s Value[sku]
+	require.NoError(t, s.Scan("acme-x1"))
+	assert.Equal(t, ValueFrom(sku{"acme", "x1"}), s)
+
+	var p Value[point]
+	require.NoError(t, p.Scan(point{1, 2}))
+	assert.Equal(t, ValueFrom(point{1, 2}), p)
+	assert.ErrorIs(t, p.Scan(int64(1)), ErrUnsupportedType)
+}
+
+func TestValue_Value(t *testing.T) {
+	v, err := ValueFrom[level](5).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), v)
+
+	v, err = ValueFrom[uint8](5).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), v)
+
+	v, err = ValueFrom[uint64](1 << 63).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "9223372036854775808", v)
+
+	v, err = ValueFrom[float32](0.5).Value()
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, v)
+
+	v, err = ValueFrom[orderID]("A1").Value()
+	require.NoError(t, err)
+	assert.Equal(t, "A1", v)
+
+	v, err = ValueFrom(sku{"acme", "x1"}).Value()
+	require.NoError(t, err)
+	assert.Equal(t, "acme-x1", v)
+
+	v, err = ValueFrom([]byte("raw")).Value()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("raw"), v)
+
+	v, err = NewValue[orderID](" ", true).Value()
+	require.NoError(t, err)
+	assert.Nil(t, v)
+}
+
+func TestValue_SQL(t *testing.T) {
+	db := openTestDB(t)
+
+	_, err := db.Exec("INSERT", ValueFrom[level](7), ValueFrom(sku{"acme", "x1"}), NewValue[orderID]("", false))
+	require.NoError(t, err)
+
+	var (
+		l  Value[level]
+		s  Value[sku]
+		id = ValueFrom[orderID]("A1")
+	)
+	require.NoError(t, db.QueryRow("SELECT").Scan(&l, &s, &id))
+	assert.Equal(t, ValueFrom[level](7), l)
+	assert.Equal(t, ValueFrom(sku{"acme", "x1"}), s)
+	assert.False(t, id.Valid)
+}
+
+func TestValue_Binary(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  []byte
+	}{
+		{name: "level", value: ValueFrom[level](-3), want: []byte{1, 5}},
+		{name: "uint8", value: ValueFrom[uint8](200), want: []byte{1, 0xc8, 0x01}},
+		{name: "float32", value: ValueFrom[float32](1), want: []byte{1, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0}},
+		{name: "bool", value: ValueFrom(true), want: []byte{1, 1}},
+		{name: "order id", value: ValueFrom[orderID]("hi"), want: []byte{1, 2, 'h', 'i'}},
+		{name: "null", value: NewValue[orderID]("", false), want: []byte{0}},
+		{name: "time", value: ValueFrom(time.Date(2023, 10, 27, 8, 0, 0, 0, time.UTC))},
+		{name: "point", value: ValueFrom(point{1, 2})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			switch v := tt.value.(type) {
+			case Value[level]:
+				assertBinaryRoundTrip(t, v, tt.want)
+			case Value[uint8]:
+				assertBinaryRoundTrip(t, v, tt.want)
+			case Value[float32]:
+				assertBinaryRoundTrip(t, v, tt.want)
+			case Value[bool]:
+				assertBinaryRoundTrip(t, v, tt.want)
+			case Value[orderID]:
+				assertBinaryRoundTrip(t, v, tt.want)
+			case Value[time.Time]:
+				assertBinaryRoundTrip(t, v, tt.want)
+			case Value[point]:
+				assertBinaryRoundTrip(t, v, tt.want)
+			}
+		})
+	}
+
+	var l Value[int8]
+	assert.ErrorIs(t, l.UnmarshalBinary([]byte{1, 0x80, 0x02}), ErrInvalidBinary)
+	var b Value[bool]
+	assert.ErrorIs(t, b.UnmarshalBinary([]byte{1, 2}), ErrInvalidBinary)
+}
+
+func assertBinaryRoundTrip[T any](t *testing.T, v Value[T], want []byte) {
+	t.Helper()
+	data, err := v.MarshalBinary()
+	require.NoError(t, err)
+	if want != nil {
+		assert.Equal(t, want, data)
+	}
+	var got Value[T]
+	require.NoError(t, got.UnmarshalBinary(data))
+	assert.Equal(t, v, got)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(v))
+	got = Value[T]{}
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&got))
+	assert.Equal(t, v, got)
+}
+
+func TestValue_BuiltinCodecs(t *testing.T) {
+	assert.Equal(t, ValueFrom[int64](5), IntFrom(5).asValue())
+
+	data, err := json.Marshal(ValueFrom(time.Date(2023, 10, 27, 8, 0, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	assert.Equal(t, `"2023-10-27T08:00:00Z"`, string(data))
+
+	assert.False(t, ValueFrom(time.Time{}).Valid)
+
+	assert.Panics(t, func() { RegisterCodec[int64](DefaultCodec[int64]{}) })
+	assert.Panics(t, func() { RegisterCodec[cents](centsCodec{}) })
+}
@@ -0,0 +1,58 @@
+package nulled
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+const xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+// XMLOmitNull controls how null values are written as XML elements. By
+// default a null element is written empty with xsi:nil="true"; set this to
+// true to leave null elements out of the output entirely. Null attributes
+// are always omitted.
+var XMLOmitNull = false
+
+// marshalXMLElement writes text as the content of start, or a nil element
+// when valid is false.
+func marshalXMLElement(e *xml.Encoder, start xml.StartElement, text string, valid bool) error {
+	if valid {
+		return e.EncodeElement(text, start)
+	}
+	if XMLOmitNull {
+		return nil
+	}
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "xmlns:xsi"}, Value: xsiNamespace},
+		xml.Attr{Name: xml.Name{Local: "xsi:nil"}, Value: "true"},
+	)
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// unmarshalXMLElement reads the character data of start. It returns false
+// for elements marked with xsi:nil="true" and for empty elements.
+func unmarshalXMLElement(d *xml.Decoder, start xml.StartElement) (string, bool, error) {
+	for _, attr := range start.Attr {
+		if isXSINil(attr) {
+			return "", false, d.Skip()
+		}
+	}
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return "", false, err
+	}
+	return s, s != "", nil
+}
+
+// isXSINil reports whether attr is xsi:nil="true". The prefix is accepted
+// even when the document doesn't declare the xsi namespace.
+func isXSINil(attr xml.Attr) bool {
+	if attr.Name.Local != "nil" || (attr.Name.Space != xsiNamespace && attr.Name.Space != "xsi") {
+		return false
+	}
+	v := strings.TrimSpace(attr.Value)
+	return v == "true" || v == "1"
+}
@@ -0,0 +1,137 @@
+package nulled
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type xmlItem struct {
+	XMLName xml.Name `xml:"item"`
+	ID      Int      `xml:"id,attr"`
+	Name    String   `xml:"name"`
+	Qty     Int      `xml:"qty"`
+	Price   Float    `xml:"price"`
+	Active  Bool     `xml:"active"`
+	Updated Time     `xml:"updated"`
+}
+
+func TestXML_Marshal(t *testing.T) {
+	item := xmlItem{
+		ID:      IntFrom(7),
+		Name:    StringFrom("pen"),
+		Qty:     IntFrom(3),
+		Price:   FloatFrom(1.5),
+		Active:  BoolFrom(true),
+		Updated: TimeFrom(testTime),
+	}
+	data, err := xml.Marshal(item)
+	require.NoError(t, err)
+	assert.Equal(t, `<item id="7"><name>pen</name><qty>3</qty><price>1.5</price><active>true</active><updated>2023-10-27T10:00:00Z</updated></item>`, string(data))
+
+	t.Run("null as xsi:nil", func(t *testing.T) {
+		data, err := xml.Marshal(xmlItem{Name: StringFrom("pen")})
+		require.NoError(t, err)
+		assert.Equal(t, `<item><name>pen</name>`+
+			`<qty xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></qty>`+
+			`<price xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></price>`+
+			`<active xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></active>`+
+			`<updated xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></updated></item>`, string(data))
+
+		var decoded xmlItem
+		require.NoError(t, xml.Unmarshal(data, &decoded))
+		assert.Equal(t, xmlItem{XMLName: xml.Name{Local: "item"}, Name: StringFrom("pen")}, decoded)
+	})
+
+	t.Run("null omitted", func(t *testing.T) {
+		defer func(omit bool) { XMLOmitNull = omit }(XMLOmitNull)
+		XMLOmitNull = true
+		data, err := xml.Marshal(xmlItem{Name: StringFrom("pen")})
+		require.NoError(t, err)
+		assert.Equal(t, `<item><name>pen</name></item>`, string(data))
+	})
+}
+
+func TestXML_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    xmlItem
+		wantErr bool
+	}{
+		{
+			name:  "values",
+			input: `<item id="7"><name>pen</name><qty> 3 </qty><price>1.5</price><active>1</active><updated>2023-10-27T10:00:00Z</updated></item>`,
+			want:  xmlItem{ID: IntFrom(7), Name: StringFrom("pen"), Qty: IntFrom(3), Price: FloatFrom(1.5), Active: BoolFrom(true), Updated: TimeFrom(testTime)},
+		},
+		{
+			name:  "empty elements",
+			input: `<item id=""><name/><qty></qty><price/><active/><updated/></item>`,
+			want:  xmlItem{},
+		},
+		{
+			name: "xsi:nil",
+			input: `<item xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+				`<name xsi:nil="true"/><qty xsi:nil="true"></qty><price xsi:nil="true"/><active xsi:nil="true"/><updated xsi:nil="true"/></item>`,
+			want: xmlItem{},
+		},
+		{
+			name:  "undeclared xsi prefix",
+			input: `<item><price xsi:nil="true">9</price></item>`,
+			want:  xmlItem{},
+		},
+		{
+			name:  "missing elements",
+			input: `<item></item>`,
+			want:  xmlItem{},
+		},
+		{name: "invalid int", input: `<item><qty>abc</qty></item>`, wantErr: true},
+		{name: "invalid attr", input: `<item id="abc"></item>`, wantErr: true},
+		{name: "invalid time", input: `<item><updated>abc</updated></item>`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var item xmlItem
+			err := xml.Unmarshal([]byte(tt.input), &item)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tt.want.XMLName = xml.Name{Local: "item"}
+			assert.Equal(t, tt.want.ID, item.ID)
+			assert.Equal(t, tt.want.Name, item.Name)
+			assert.Equal(t, tt.want.Qty, item.Qty)
+			assert.Equal(t, tt.want.Price.Valid, item.Price.Valid)
+			assert.Equal(t, tt.want.Price.Float64, item.Price.Float64)
+			assert.Equal(t, tt.want.Active.Valid, item.Active.Valid)
+			assert.Equal(t, tt.want.Active.Bool, item.Active.Bool)
+			assert.Equal(t, tt.want.Updated.Valid, item.Updated.Valid)
+			assert.True(t, tt.want.Updated.Time.Equal(item.Updated.Time))
+		})
+	}
+}
+
+func TestXML_SizedNumbers(t *testing.T) {
+	type sized struct {
+		XMLName xml.Name `xml:"item"`
+		ID      Uint32   `xml:"id,attr"`
+		Qty     Int16    `xml:"qty"`
+		Price   Float32  `xml:"price"`
+	}
+	data, err := xml.Marshal(sized{ID: Uint32From(7), Price: Float32From(1.1)})
+	require.NoError(t, err)
+	assert.Equal(t, `<item id="7"><qty xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:nil="true"></qty><price>1.1</price></item>`, string(data))
+
+	var out sized
+	require.NoError(t, xml.Unmarshal(data, &out))
+	assert.Equal(t, sized{XMLName: xml.Name{Local: "item"}, ID: Uint32From(7), Price: Float32From(1.1)}, out)
+
+	require.NoError(t, xml.Unmarshal([]byte(`<item><qty> 3 </qty></item>`), &out))
+	assert.Equal(t, Int16From(3), out.Qty)
+	assert.Error(t, xml.Unmarshal([]byte(`<item id="-1"></item>`), &out))
+	assert.Error(t, xml.Unmarshal([]byte(`<item><qty>70000</qty></item>`), &out))
+}
@@ -0,0 +1,162 @@
+package nulled
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// isYAMLNull reports whether n is a YAML null: ~, null or an empty value.
+//
+// yaml.v3 doesn't call UnmarshalYAML for null nodes at all and leaves the
+// destination untouched, so a null or missing key decodes to the zero value,
+// which is null for every type in this package. The check covers callers
+// that invoke UnmarshalYAML directly, and the Optional fields that
+// UnmarshalYAML marks as present.
+func isYAMLNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
+
+// yamlText returns the text of the scalar value decoded into typ, or false
+// if value is null.
+func yamlText(value *yaml.Node, typ string) (string, bool, error) {
+	if isYAMLNull(value) {
+		return "", false, nil
+	}
+	if value.Kind != yaml.ScalarNode {
+		return "", false, fmt.Errorf("yaml: line %d: cannot unmarshal %s into %s", value.Line, value.ShortTag(), typ)
+	}
+	return value.Value, true, nil
+}
+
+// yamlScalar returns text as a YAML scalar with the given tag. yaml.v3
+// writes it plain if it reads back with that tag, and quoted or tagged if
+// not.
+func yamlScalar(tag, text string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: text}
+}
+
+// yamlNumber returns text as a YAML number if it is a finite one, and as a
+// string otherwise.
+func yamlNumber(text string) *yaml.Node {
+	if _, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return yamlScalar("!!int", text)
+	}
+	if _, err := strconv.ParseUint(text, 10, 64); err == nil {
+		return yamlScalar("!!int", text)
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil && !math.IsInf(f, 0) {
+		return yamlScalar("!!float", text)
+	}
+	return yamlScalar("!!str", text)
+}
+
+// UnmarshalYAML decodes data into v like yaml.Unmarshal, and also marks the
+// Optional fields whose key is set to null as present and null. yaml.v3
+// doesn't call unmarshalers for null values, so with yaml.Unmarshal they
+// can't be told apart from missing keys:
+//
+//	var patch UserPatch
+//	err := nulled.UnmarshalYAML([]byte("name: null\n"), &patch)
+//	// patch.Name.Present == true, patch.Name.IsNull() == true
+func UnmarshalYAML(data []byte, v any) error {
+	var n yaml.Node
+	if err := yaml.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	return DecodeYAML(&n, v)
+}
+
+// DecodeYAML decodes n into v like n.Decode, marking the Optional fields set
+// to null the way UnmarshalYAML does. Use it in the UnmarshalYAML method of
+// a type that holds Optional fields.
+func DecodeYAML(n *yaml.Node, v any) error {
+	if n.Kind == 0 {
+		return nil
+	}
+	if err := n.Decode(v); err != nil {
+		return err
+	}
+	markYAMLNulls(n, reflect.ValueOf(v))
+	return nil
+}
+
+// markYAMLNulls calls SetNull on the Optional fields of v whose node in n is
+// null, recursing into nested structs and slices.
+func markYAMLNulls(n *yaml.Node, v reflect.Value) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 1 {
+			markYAMLNulls(n.Content[0], v)
+		}
+		return
+	case yaml.AliasNode:
+		markYAMLNulls(n.Alias, v)
+		return
+	}
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch {
+	case n.Kind == yaml.MappingNode && v.Kind() == reflect.Struct:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			f, ok := yamlField(v, n.Content[i].Value)
+			if !ok || !f.CanAddr() || !f.Addr().CanInterface() {
+				continue
+			}
+			value := n.Content[i+1]
+			for value.Kind == yaml.AliasNode {
+				value = value.Alias
+			}
+			if _, ok := f.Interface().(optional); ok && isYAMLNull(value) {
+				f.Addr().Interface().(interface{ SetNull() }).SetNull()
+				continue
+			}
+			markYAMLNulls(value, f)
+		}
+	case n.Kind == yaml.SequenceNode && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array):
+		for i, c := range n.Content {
+			if i < v.Len() {
+				markYAMLNulls(c, v.Index(i))
+			}
+		}
+	}
+}
+
+// yamlField returns the field of the struct v that yaml.v3 decodes key
+// into, looking into inlined structs.
+func yamlField(v reflect.Value, key string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(","+opts+",", ",inline,") {
+			if f.Type.Kind() == reflect.Struct {
+				if fv, ok := yamlField(v.Field(i), key); ok {
+					return fv, true
+				}
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		if name == key {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,134 @@
+package nulled
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type yamlConfig struct {
+	S String `yaml:"s"`
+	I Int    `yaml:"i"`
+	F Float  `yaml:"f"`
+	B Bool   `yaml:"b"`
+	T Time   `yaml:"t"`
+}
+
+func TestYAML_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    yamlConfig
+		wantErr bool
+	}{
+		{
+			name:  "values",
+			input: "s: hello\ni: 42\nf: 1.5\nb: true\nt: 2023-10-27T10:00:00Z\n",
+			want:  yamlConfig{S: StringFrom("hello"), I: IntFrom(42), F: FloatFrom(1.5), B: BoolFrom(true), T: TimeFrom(testTime)},
+		},
+		{
+			name:  "zero values",
+			input: "s: \"\"\ni: 0\nf: 0\nb: false\n",
+			want:  yamlConfig{S: NewString("", false), I: IntFrom(0), F: FloatFrom(0), B: BoolFrom(false)},
+		},
+		{
+			name:  "tilde",
+			input: "s: ~\ni: ~\nf: ~\nb: ~\nt: ~\n",
+			want:  yamlConfig{},
+		},
+		{
+			name:  "null",
+			input: "s: null\ni: null\nf: null\nb: null\nt: null\n",
+			want:  yamlConfig{},
+		},
+		{
+			name:  "empty values",
+			input: "s:\ni:\nf:\nb:\nt:\n",
+			want:  yamlConfig{},
+		},
+		{
+			name:  "missing keys",
+			input: "{}",
+			want:  yamlConfig{},
+		},
+		{name: "invalid int", input: "i: abc", wantErr: true},
+		{name: "invalid time", input: "t: abc", wantErr: true},
+		{name: "time mapping", input: "t: {a: 1}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c yamlConfig
+			err := yaml.Unmarshal([]byte(tt.input), &c)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, c)
+		})
+	}
+}
+
+func TestYAML_Marshal(t *testing.T) {
+	data, err := yaml.Marshal(yamlConfig{S: StringFrom("hello"), I: IntFrom(42), F: FloatFrom(1.5), B: BoolFrom(false), T: TimeFrom(testTime)})
+	require.NoError(t, err)
+	assert.Equal(t, "s: hello\ni: 42\nf: 1.5\nb: false\nt: 2023-10-27T10:00:00Z\n", string(data))
+
+	data, err = yaml.Marshal(yamlConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "s: null\ni: null\nf: null\nb: null\nt: null\n", string(data))
+
+	var c yamlConfig
+	require.NoError(t, yaml.Unmarshal(data, &c))
+	assert.Equal(t, yamlConfig{}, c)
+}
+
+func TestYAML_UnmarshalNullNode(t *testing.T) {
+	var n yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("~"), &n))
+
+	s := StringFrom("hello")
+	require.NoError(t, s.UnmarshalYAML(n.Content[0]))
+	assert.False(t, s.Valid)
+
+	i := IntFrom(1)
+	require.NoError(t, i.UnmarshalYAML(n.Content[0]))
+	assert.False(t, i.Valid)
+
+	ti := TimeFrom(testTime)
+	require.NoError(t, ti.UnmarshalYAML(n.Content[0]))
+	assert.False(t, ti.Valid)
+}
+
+func TestYAML_SizedNumbers(t *testing.T) {
+	type sized struct {
+		A Int32         `yaml:"a"`
+		U Uint64        `yaml:"u"`
+		F Float32       `yaml:"f"`
+		N Int8          `yaml:"i8"`
+		V Value[string] `yaml:"v"`
+	}
+	data, err := yaml.Marshal(sized{A: Int32From(5), U: Uint64From(1 << 63), F: Float32From(1.1), V: ValueFrom("true")})
+	require.NoError(t, err)
+	assert.Equal(t, "a: 5\nu: 9223372036854775808\nf: 1.1\ni8: null\nv: \"true\"\n", string(data))
+
+	var out sized
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	assert.Equal(t, sized{A: Int32From(5), U: Uint64From(1 << 63), F: Float32From(1.1), V: ValueFrom("true")}, out)
+
+	out = sized{}
+	require.NoError(t, yaml.Unmarshal([]byte("a: 7\nf: \" 2.5 \"\n"), &out))
+	assert.Equal(t, Int32From(7), out.A)
+	assert.Equal(t, Float32From(2.5), out.F)
+	assert.Error(t, yaml.Unmarshal([]byte("i8: 300"), &out), "out of range")
+	assert.Error(t, yaml.Unmarshal([]byte("a: [1]"), &out))
+
+	data, err = yaml.Marshal(sized{F: NewFloat32(float32(math.Inf(1)), true)})
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &out))
+	assert.True(t, math.IsInf(float64(out.F.V), 1))
+}